@@ -0,0 +1,49 @@
+package twitterstream
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Environment variables read by NewFromEnv.
+const (
+	EnvBearerToken = "TWITTER_BEARER_TOKEN"
+	EnvApiKey      = "TWITTER_API_KEY"
+	EnvApiSecret   = "TWITTER_API_SECRET"
+)
+
+// ErrMissingCredentials is returned by NewFromEnv when neither a bearer token nor an api key and secret are set.
+var ErrMissingCredentials = errors.New("missing twitter credentials in the environment")
+
+// NewFromEnv creates a TwitterApi with credentials from the environment, for twelve-factor apps.
+// If TWITTER_BEARER_TOKEN is set, it is used as is. Otherwise TWITTER_API_KEY and TWITTER_API_SECRET are both
+// required, and a bearer token is requested with them, using the http client options in `opts`.
+// ErrMissingCredentials is returned, naming the missing variables, if there are no usable credentials.
+func NewFromEnv(opts ...Option) (*TwitterApi, error) {
+	if token := os.Getenv(EnvBearerToken); token != "" {
+		return NewTwitterStream(token, opts...), nil
+	}
+
+	apiKey, apiSecret := os.Getenv(EnvApiKey), os.Getenv(EnvApiSecret)
+	var missing []string
+	if apiKey == "" {
+		missing = append(missing, EnvApiKey)
+	}
+	if apiSecret == "" {
+		missing = append(missing, EnvApiSecret)
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%w: set %s, or %v", ErrMissingCredentials, EnvBearerToken, missing)
+	}
+
+	tok, err := NewTokenGenerator(opts...).SetApiKeyAndSecret(apiKey, apiSecret).RequestBearerToken()
+	if err != nil {
+		return nil, err
+	}
+	if tok.AccessToken == "" {
+		return nil, fmt.Errorf("no bearer token was returned for %s", EnvApiKey)
+	}
+
+	return NewTwitterStream(tok.AccessToken, opts...), nil
+}
@@ -0,0 +1,60 @@
+package twitterstream
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"dev.freespoke.com/twitter-stream/httpclient"
+)
+
+func TestNewFromEnv(t *testing.T) {
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		fmt.Fprint(w, `{"token_type": "bearer", "access_token": "generated"}`)
+	}))
+	defer server.Close()
+
+	var tests = []struct {
+		name    string
+		env     map[string]string
+		err     error
+		missing string
+	}{
+		{"bearer token", map[string]string{EnvBearerToken: "token"}, nil, ""},
+		{"api key and secret", map[string]string{EnvApiKey: "key", EnvApiSecret: "secret"}, nil, ""},
+		{"nothing", map[string]string{}, ErrMissingCredentials, EnvApiKey + " " + EnvApiSecret},
+		{"no secret", map[string]string{EnvApiKey: "key"}, ErrMissingCredentials, EnvApiSecret},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, name := range []string{EnvBearerToken, EnvApiKey, EnvApiSecret} {
+				t.Setenv(name, tt.env[name])
+			}
+			authorization = ""
+
+			api, err := NewFromEnv(WithHttpClientOptions(httpclient.WithBaseURL(server.URL)))
+
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("got err %v, want %v", err, tt.err)
+			}
+			if err != nil {
+				if !strings.Contains(err.Error(), tt.missing) {
+					t.Errorf("got err %v, want it to name %s", err, tt.missing)
+				}
+				return
+			}
+			if api == nil || api.Stream == nil || api.Rules == nil {
+				t.Errorf("got %+v, want a ready api", api)
+			}
+			if _, usesKeys := tt.env[EnvApiKey]; usesKeys != (authorization != "") {
+				t.Errorf("got token request %v, want one only with an api key", authorization != "")
+			}
+		})
+	}
+}
@@ -1,3 +1,3 @@
 module dev.freespoke.com/twitter-stream
 
-go 1.16
+go 1.18
@@ -0,0 +1,43 @@
+package httpclient
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrConnectionLimit is returned when Twitter rejects a stream because the credentials already have the maximum
+// number of streaming connections open. Retrying will not help until the duplicate connection is closed,
+// so it should be treated as fatal. Use errors.Is to detect it.
+var ErrConnectionLimit = errors.New("streaming connection limit exceeded")
+
+// ErrUnauthorized is returned when Twitter rejects the credentials with 401 Unauthorized or 403 Forbidden,
+// for example because a token was revoked or the app lost access to the endpoint. Use errors.Is to detect it.
+var ErrUnauthorized = errors.New("credentials were rejected")
+
+// RateLimitError is returned when Twitter keeps answering a request with 429 Too Many Requests after
+// the retries allowed by WithRateLimitRetries. RetryAfter is how long Twitter asked to wait before trying again,
+// parsed from the Retry-After or x-rate-limit-reset headers. It is 0 if Twitter didn't say.
+// Use errors.As to detect it.
+type RateLimitError struct {
+	RetryAfter time.Duration
+	Message    string
+}
+
+// Error implements the error interface.
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("rate limited, retry after %v: %s", e.RetryAfter, e.Message)
+}
+
+// StatusError is returned when Twitter answers a request with a 4xx or 5xx status code, other than those
+// returned as ErrUnauthorized, ErrConnectionLimit, or a RateLimitError. Message holds the body of the response.
+// Use errors.As to detect it.
+type StatusError struct {
+	StatusCode int
+	Message    string
+}
+
+// Error implements the error interface.
+func (e *StatusError) Error() string {
+	return e.Message
+}
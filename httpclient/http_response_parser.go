@@ -1,33 +1,53 @@
 package httpclient
 
 import (
-	"errors"
+	"bytes"
 	"fmt"
 	"io/ioutil"
 	"log"
 	"math"
 	"net/http"
+	"strconv"
 	"time"
 )
 
 // httpResponseParser is a struct that will retry network requests if the response has a status code of 429.
-type httpResponseParser struct{}
+// When limitRetries is set, a *RateLimitError is returned once a request was retried maxRetries times.
+type httpResponseParser struct {
+	limitRetries bool
+	maxRetries   uint8
+}
 
 func (h httpResponseParser) handleResponse(resp *http.Response, opts *RequestOpts, fn func(opts *RequestOpts) (*http.Response, error)) (*http.Response, error) {
-	// Retry with backoff if 429
-	if resp.StatusCode == 429 {
-		log.Printf("Retrying network request %s with backoff", opts.Url)
-
+	// Retry with backoff if 429, unless the connection limit was reached
+	if resp.StatusCode == 409 || resp.StatusCode == 429 {
+		var body []byte
 		var msg string
 		if resp.Body != nil {
-			body, _ := ioutil.ReadAll(resp.Body)
+			body, _ = ioutil.ReadAll(resp.Body)
 			msg = "Network request failed: " + string(body)
 		} else {
 			msg = "Network request failed with status: " + fmt.Sprint(resp.StatusCode)
 		}
+
+		if h.isConnectionLimit(resp.StatusCode, body) {
+			log.Printf("Network Request at %s failed: %v", opts.Url, resp.StatusCode)
+			return nil, fmt.Errorf("%w: %s", ErrConnectionLimit, msg)
+		}
+
+		retryAfter := h.getRetryAfter(resp.Header, time.Now())
+		if h.limitRetries && opts.Retries >= h.maxRetries {
+			log.Printf("Network Request at %s failed: %v", opts.Url, resp.StatusCode)
+			return nil, &RateLimitError{RetryAfter: retryAfter, Message: msg}
+		}
+
+		log.Printf("Retrying network request %s with backoff", opts.Url)
 		log.Printf(msg)
 
 		delay := h.getBackOffTime(opts.Retries)
+		if retryAfter > 0 {
+			delay = retryAfter
+		}
 		log.Printf("Sleeping for %v seconds", delay)
 		time.Sleep(delay)
 
@@ -48,12 +68,25 @@ func (h httpResponseParser) handleResponse(resp *http.Response, opts *RequestOpt
 			msg = "Network request failed with status" + fmt.Sprint(resp.StatusCode)
 		}
 
-		return nil, errors.New(msg)
+		if resp.StatusCode == 401 || resp.StatusCode == 403 {
+			return nil, fmt.Errorf("%w: %s", ErrUnauthorized, msg)
+		}
+
+		return nil, &StatusError{StatusCode: resp.StatusCode, Message: msg}
 	}
 
 	return resp, nil
 }
 
+// isConnectionLimit reports whether a response means too many streaming connections are open.
+// Twitter answers with a 409, or with a 429 whose body names the "TooManyConnections" problem.
+func (h httpResponseParser) isConnectionLimit(statusCode int, body []byte) bool {
+	if statusCode == 409 {
+		return true
+	}
+	return bytes.Contains(body, []byte("TooManyConnections")) || bytes.Contains(body, []byte("connection limit"))
+}
+
 func (h httpResponseParser) getBackOffTime(retries uint8) time.Duration {
 	exponentialBackoffCeilingSecs := 30
 	delaySecs := int(math.Floor((math.Pow(2, float64(retries)) - 1) * 0.5))
@@ -62,3 +95,28 @@ func (h httpResponseParser) getBackOffTime(retries uint8) time.Duration {
 	}
 	return time.Duration(delaySecs) * time.Second
 }
+
+// getRetryAfter returns how long Twitter asked us to wait before retrying, or 0 if it didn't say.
+// The Retry-After header is parsed in both its seconds and HTTP-date forms. When it is missing,
+// the x-rate-limit-reset header, a unix timestamp, is used instead.
+func (h httpResponseParser) getRetryAfter(header http.Header, now time.Time) time.Duration {
+	if value := header.Get("Retry-After"); value != "" {
+		if secs, err := strconv.Atoi(value); err == nil && secs > 0 {
+			return time.Duration(secs) * time.Second
+		}
+		if date, err := http.ParseTime(value); err == nil && date.After(now) {
+			return date.Sub(now)
+		}
+		return 0
+	}
+
+	if value := header.Get("x-rate-limit-reset"); value != "" {
+		if reset, err := strconv.ParseInt(value, 10, 64); err == nil {
+			if date := time.Unix(reset, 0); date.After(now) {
+				return date.Sub(now)
+			}
+		}
+	}
+
+	return 0
+}
@@ -1,8 +1,13 @@
 package httpclient
 
 import (
+	"errors"
+	"fmt"
+	"io/ioutil"
 	"net/http"
+	"strings"
 	"testing"
+	"time"
 )
 
 func givenHttpResponseParserInstance() *httpResponseParser {
@@ -68,3 +73,113 @@ func TestHandleResponseShouldRejectIf400OrHigher(t *testing.T) {
 		t.Errorf("Expected error, got nil")
 	}
 }
+
+func TestHandleResponseShouldRejectUnauthorizedIf401Or403(t *testing.T) {
+	for _, statusCode := range []int{401, 403} {
+		instance := givenHttpResponseParserInstance()
+		opts := new(RequestOpts)
+		resp := givenFakeHttpResponse(statusCode)
+
+		_, err := instance.handleResponse(resp, opts, func(o *RequestOpts) (*http.Response, error) {
+			return nil, nil
+		})
+
+		if !errors.Is(err, ErrUnauthorized) {
+			t.Errorf("Expected ErrUnauthorized for %d, got %v", statusCode, err)
+		}
+	}
+}
+
+func TestHandleResponseShouldRejectWithStatusError(t *testing.T) {
+	for _, statusCode := range []int{400, 503} {
+		instance := givenHttpResponseParserInstance()
+		opts := new(RequestOpts)
+		resp := givenFakeHttpResponse(statusCode)
+
+		_, err := instance.handleResponse(resp, opts, func(o *RequestOpts) (*http.Response, error) {
+			return nil, nil
+		})
+
+		var statusErr *StatusError
+		if !errors.As(err, &statusErr) || statusErr.StatusCode != statusCode {
+			t.Errorf("Expected a StatusError for %d, got %v", statusCode, err)
+		}
+	}
+}
+
+func TestHandleResponseShouldRejectConnectionLimitIf409(t *testing.T) {
+	instance := givenHttpResponseParserInstance()
+	opts := new(RequestOpts)
+	resp := givenFakeHttpResponse(409)
+
+	_, err := instance.handleResponse(resp, opts, func(o *RequestOpts) (*http.Response, error) {
+		return givenFakeHttpResponse(200), nil
+	})
+
+	if !errors.Is(err, ErrConnectionLimit) {
+		t.Errorf("Expected ErrConnectionLimit, got %v", err)
+	}
+
+	if opts.Retries != 0 {
+		t.Errorf("Expected no retry attempts, got %v", opts.Retries)
+	}
+}
+
+func TestHandleResponseShouldRejectConnectionLimitIf429TooManyConnections(t *testing.T) {
+	instance := givenHttpResponseParserInstance()
+	opts := new(RequestOpts)
+	resp := givenFakeHttpResponse(429)
+	resp.Body = ioutil.NopCloser(strings.NewReader(`{"title":"ConnectionException","connection_issue":"TooManyConnections"}`))
+
+	_, err := instance.handleResponse(resp, opts, func(o *RequestOpts) (*http.Response, error) {
+		return givenFakeHttpResponse(200), nil
+	})
+
+	if !errors.Is(err, ErrConnectionLimit) {
+		t.Errorf("Expected ErrConnectionLimit, got %v", err)
+	}
+}
+
+func TestHandleResponseShouldReturnRateLimitErrorOnceRetriesAreExhausted(t *testing.T) {
+	instance := &httpResponseParser{limitRetries: true, maxRetries: 1}
+	opts := new(RequestOpts)
+	opts.Retries = 1
+	resp := givenFakeHttpResponse(429)
+	resp.Header = http.Header{"Retry-After": []string{"120"}}
+
+	_, err := instance.handleResponse(resp, opts, func(o *RequestOpts) (*http.Response, error) {
+		return givenFakeHttpResponse(200), nil
+	})
+
+	var rateLimitErr *RateLimitError
+	if !errors.As(err, &rateLimitErr) {
+		t.Fatalf("Expected RateLimitError, got %v", err)
+	}
+
+	if rateLimitErr.RetryAfter != 120*time.Second {
+		t.Errorf("Expected 2m0s, got %v", rateLimitErr.RetryAfter)
+	}
+}
+
+func TestGetRetryAfter(t *testing.T) {
+	now := time.Date(2021, 12, 1, 12, 0, 0, 0, time.UTC)
+	var tests = []struct {
+		header http.Header
+		result time.Duration
+	}{
+		{http.Header{}, 0},
+		{http.Header{"Retry-After": []string{"30"}}, 30 * time.Second},
+		{http.Header{"Retry-After": []string{"Wed, 01 Dec 2021 12:01:30 GMT"}}, 90 * time.Second},
+		{http.Header{"Retry-After": []string{"Wed, 01 Dec 2021 11:00:00 GMT"}}, 0},
+		{http.Header{"Retry-After": []string{"soon"}}, 0},
+		{http.Header{"X-Rate-Limit-Reset": []string{fmt.Sprint(now.Add(time.Minute).Unix())}}, time.Minute},
+	}
+
+	instance := givenHttpResponseParserInstance()
+	for i, tt := range tests {
+		result := instance.getRetryAfter(tt.header, now)
+		if result != tt.result {
+			t.Errorf("(%d) got %v, want %v", i, result, tt.result)
+		}
+	}
+}
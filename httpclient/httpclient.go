@@ -2,18 +2,42 @@ package httpclient
 
 import (
 	"bytes"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 )
 
 type twitterEndpoints map[string]string
 
-// Endpoints is a map of twitter endpoints used to manage rules and streams.
-var Endpoints = make(twitterEndpoints)
+// DefaultBaseURL is the host every endpoint is requested from unless WithBaseURL is used.
+const DefaultBaseURL = "https://api.twitter.com"
+
+// DefaultUserAgent is the User-Agent WithUserAgentSuffix appends to unless WithUserAgent is used.
+const DefaultUserAgent = "twitter-stream"
+
+// endpointPaths is a map of twitter endpoint paths relative to the base url.
+var endpointPaths = twitterEndpoints{
+	"rules":  "/2/tweets/search/stream/rules",
+	"stream": "/2/tweets/search/stream",
+	"token":  "/oauth2/token",
+}
+
+// Endpoints is a map of twitter endpoints used to manage rules and streams, on DefaultBaseURL.
+//
+// Deprecated: requests are made to the base url of each client, which WithBaseURL can change, and this map is
+// no longer read. Use GenerateUrl to get the url of an endpoint for a client.
+var Endpoints = func() twitterEndpoints {
+	endpoints := make(twitterEndpoints)
+	for name, path := range endpointPaths {
+		endpoints[name] = DefaultBaseURL + path
+	}
+	return endpoints
+}()
 
 type (
 	// IHttpClient is the interface the httpClient struct implements.
@@ -25,24 +49,133 @@ type (
 		GenerateUrl(name string, queryParams *url.Values) (string, error)
 	}
 
+	// Option configures an httpClient created with NewHttpClient.
+	Option func(*httpClient)
+
 	httpClient struct {
-		token string
+		token              string
+		baseURL            string
+		insecureSkipVerify bool
+		requestTimeout     time.Duration
+		readDeadline       time.Duration
+		userAgent          string
+		userAgentSuffix    string
+		oauth1             *oauth1Signer
+		responseParser     httpResponseParser
+		client             *http.Client
+		streamClient       *http.Client
 	}
 )
 
 // NewHttpClient constructs a an HttpClient to interact with twitter.
-func NewHttpClient(token string) IHttpClient {
-	Endpoints["rules"] = "https://api.twitter.com/2/tweets/search/stream/rules"
-	Endpoints["stream"] = "https://api.twitter.com/2/tweets/search/stream"
-	Endpoints["token"] = "https://api.twitter.com/oauth2/token"
-	return &httpClient{token}
+func NewHttpClient(token string, opts ...Option) IHttpClient {
+	client := &httpClient{token: token, baseURL: DefaultBaseURL}
+	for _, opt := range opts {
+		opt(client)
+	}
+	transport := client.newTransport()
+	client.client = &http.Client{Transport: transport, Timeout: client.requestTimeout}
+	client.streamClient = &http.Client{Transport: transport}
+	if client.readDeadline > 0 {
+		// the stream gets its own connections, so idle rules and token connections don't hit the deadline
+		client.streamClient.Transport = withReadDeadline(client.newTransport(), client.readDeadline)
+	}
+	return client
+}
+
+// WithBaseURL sets the host used for stream, rules, and token requests, e.g. "http://localhost:8080".
+// It is meant for pointing the client at a local mock server or an approved mirror.
+// Defaults to DefaultBaseURL.
+func WithBaseURL(baseURL string) Option {
+	return func(t *httpClient) {
+		t.baseURL = strings.TrimRight(baseURL, "/")
+	}
+}
+
+// WithInsecureSkipVerify disables TLS certificate verification for every request.
+//
+// WARNING: this is for testing against a local mock server with a self-signed certificate only.
+// NEVER use it in production. It makes every request, including ones carrying your bearer token and
+// api secret, trivially interceptable by anyone on the network path.
+func WithInsecureSkipVerify(insecureSkipVerify bool) Option {
+	return func(t *httpClient) {
+		t.insecureSkipVerify = insecureSkipVerify
+	}
+}
+
+// WithRequestTimeout limits how long rules and token requests may take, including reading the response body.
+// It does not apply to the streaming connection, which is expected to stay open indefinitely.
+// Defaults to 0, which means no timeout.
+func WithRequestTimeout(timeout time.Duration) Option {
+	return func(t *httpClient) {
+		t.requestTimeout = timeout
+	}
+}
+
+// WithRateLimitRetries limits how many times a request answered with 429 Too Many Requests is retried.
+// Once the limit is reached, a *RateLimitError is returned with the delay Twitter asked for, so a control loop can wait
+// exactly that long. Use 0 to never retry. By default, 429s are retried with backoff until they succeed.
+func WithRateLimitRetries(n uint8) Option {
+	return func(t *httpClient) {
+		t.responseParser = httpResponseParser{limitRetries: true, maxRetries: n}
+	}
+}
+
+// WithUserAgent sets the User-Agent header of every request. By default, Go's User-Agent is sent.
+func WithUserAgent(userAgent string) Option {
+	return func(t *httpClient) {
+		t.userAgent = userAgent
+	}
+}
+
+// WithUserAgentSuffix appends a suffix, like a hostname or UUID, to the User-Agent set by WithUserAgent,
+// or to DefaultUserAgent. This makes each consumer in a fleet sharing an egress IP distinguishable in logs,
+// and keeps their requests from looking identical to intermediaries.
+func WithUserAgentSuffix(suffix string) Option {
+	return func(t *httpClient) {
+		t.userAgentSuffix = suffix
+	}
+}
+
+// userAgentHeader is the User-Agent to send, or empty to send Go's.
+func (t *httpClient) userAgentHeader() string {
+	if t.userAgentSuffix == "" {
+		return t.userAgent
+	}
+	userAgent := t.userAgent
+	if userAgent == "" {
+		userAgent = DefaultUserAgent
+	}
+	return userAgent + " " + t.userAgentSuffix
+}
+
+// CloseIdleConnections closes the idle connections of the client, so the next request dials a new one.
+// It does not interrupt connections in use, such as an open stream.
+func (t *httpClient) CloseIdleConnections() {
+	t.client.CloseIdleConnections()
+	t.streamClient.CloseIdleConnections()
+}
+
+// newTransport creates the transport requests are made with, based on http.DefaultTransport.
+func (t *httpClient) newTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if t.insecureSkipVerify {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+	return transport
 }
 
 // GetRules will return the current rules available for a specific API key.
 func (t *httpClient) GetRules() (*http.Response, error) {
+	url, err := t.GenerateUrl("rules", nil)
+
+	if err != nil {
+		return nil, err
+	}
+
 	res, err := t.NewHttpRequest(&RequestOpts{
 		Method: "GET",
-		Url:    Endpoints["rules"],
+		Url:    url,
 		Body:   "",
 	})
 
@@ -80,6 +213,7 @@ func (t *httpClient) GetSearchStream(queryParams *url.Values) (*http.Response, e
 	}
 
 	res, err := t.NewHttpRequest(&RequestOpts{
+		Stream: true,
 		Method: "GET",
 		Url:    url,
 	})
@@ -93,18 +227,17 @@ func (t *httpClient) GetSearchStream(queryParams *url.Values) (*http.Response, e
 
 // GenerateUrl is a utility function for httpclient package to generate a valid url for api.twitter.
 func (t *httpClient) GenerateUrl(name string, queryParams *url.Values) (string, error) {
-	var url string
-	if queryParams != nil {
-		url = Endpoints[name] + fmt.Sprintf("?%v", queryParams.Encode())
-	} else {
-		url = Endpoints[name]
+	path, ok := endpointPaths[name]
+	if !ok {
+		return "", errors.New("Could not find endpoint with name " + name)
 	}
 
-	if len(url) == 0 || !strings.HasPrefix(url, "https://api.twitter.com") {
-		return url, errors.New("Could not find endpoint with name " + name)
-	} else {
-		return url, nil
+	url := t.baseURL + path
+	if queryParams != nil {
+		url += fmt.Sprintf("?%v", queryParams.Encode())
 	}
+
+	return url, nil
 }
 
 // NewHttpRequest performs an authenticated http request with twitter with the token this httpclient has.
@@ -126,26 +259,39 @@ func (t *httpClient) NewHttpRequest(opts *RequestOpts) (*http.Response, error) {
 
 	// Set Headers
 	req.Header.Set("Content-Type", "application/json")
+	if userAgent := t.userAgentHeader(); userAgent != "" {
+		req.Header.Set("User-Agent", userAgent)
+	}
 	if len(opts.Headers) > 0 {
 		for _, header := range opts.Headers {
 			req.Header.Set(header.Key, header.Value)
 		}
 	}
 
-	// Set token if this httpclient has a token set
-	if len(t.token) > 0 {
+	// Sign with user context, or set token if this httpclient has a token set
+	if t.oauth1 != nil {
+		if req.Header.Get("Authorization") == "" {
+			var form url.Values
+			if strings.HasPrefix(req.Header.Get("Content-Type"), "application/x-www-form-urlencoded") {
+				form, _ = url.ParseQuery(opts.Body)
+			}
+			req.Header.Set("Authorization", t.oauth1.authorization(opts.Method, req.URL, form))
+		}
+	} else if len(t.token) > 0 {
 		req.Header.Set("Authorization", "Bearer "+t.token)
 	}
 
 	// Perform network request
-	client := &http.Client{}
+	client := t.client
+	if opts.Stream {
+		client = t.streamClient
+	}
 	resp, err := client.Do(req)
 	if err != nil {
 		log.Printf("Failed to perform request for %s: %v", opts.Url, err)
 		return nil, err
 	}
 
-	responseParser := new(httpResponseParser)
-	return responseParser.handleResponse(resp, opts, t.NewHttpRequest)
+	return t.responseParser.handleResponse(resp, opts, t.NewHttpRequest)
 
 }
@@ -0,0 +1,165 @@
+package httpclient
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestGenerateUrlUsesDefaultBaseURL(t *testing.T) {
+	instance := NewHttpClient("sometoken")
+	query := new(url.URL).Query()
+	query.Add("dry_run", "true")
+
+	result, err := instance.GenerateUrl("rules", &query)
+
+	if err != nil {
+		t.Errorf("got err %v", err)
+	}
+
+	expected := "https://api.twitter.com/2/tweets/search/stream/rules?dry_run=true"
+	if result != expected {
+		t.Errorf("got %s, want %s", result, expected)
+	}
+}
+
+func TestGenerateUrlRejectsUnknownEndpoint(t *testing.T) {
+	instance := NewHttpClient("sometoken")
+
+	_, err := instance.GenerateUrl("nope", nil)
+
+	if err == nil {
+		t.Errorf("Expected error, got nil")
+	}
+}
+
+func TestWithBaseURLRedirectsRequests(t *testing.T) {
+	var path, auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		auth = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	instance := NewHttpClient("sometoken", WithBaseURL(server.URL+"/"))
+	res, err := instance.GetRules()
+
+	if err != nil {
+		t.Fatalf("got err %v", err)
+	}
+	res.Body.Close()
+
+	if path != "/2/tweets/search/stream/rules" {
+		t.Errorf("got %s, want %s", path, "/2/tweets/search/stream/rules")
+	}
+
+	if auth != "Bearer sometoken" {
+		t.Errorf("got %s, want %s", auth, "Bearer sometoken")
+	}
+}
+
+func TestWithInsecureSkipVerifyAcceptsSelfSignedCertificates(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	if _, err := NewHttpClient("sometoken", WithBaseURL(server.URL)).GetRules(); err == nil {
+		t.Errorf("Expected certificate error, got nil")
+	}
+
+	res, err := NewHttpClient("sometoken", WithBaseURL(server.URL), WithInsecureSkipVerify(true)).GetRules()
+	if err != nil {
+		t.Fatalf("got err %v", err)
+	}
+	res.Body.Close()
+}
+
+func TestWithRequestTimeoutDoesNotApplyToStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	instance := NewHttpClient("sometoken", WithBaseURL(server.URL), WithRequestTimeout(10*time.Millisecond))
+
+	if _, err := instance.GetRules(); err == nil {
+		t.Errorf("Expected timeout error for rules, got nil")
+	}
+
+	res, err := instance.GetSearchStream(nil)
+	if err != nil {
+		t.Fatalf("got err %v", err)
+	}
+	res.Body.Close()
+}
+
+func TestWithUserAgent(t *testing.T) {
+	var tests = []struct {
+		opts []Option
+		want string
+	}{
+		{[]Option{WithUserAgent("my-consumer/1.0")}, "my-consumer/1.0"},
+		{[]Option{WithUserAgentSuffix("host-1")}, DefaultUserAgent + " host-1"},
+		{[]Option{WithUserAgentSuffix("host-1"), WithUserAgent("my-consumer/1.0")}, "my-consumer/1.0 host-1"},
+	}
+
+	for i, tt := range tests {
+		testName := fmt.Sprintf("TestWithUserAgent (%d)", i)
+
+		t.Run(testName, func(t *testing.T) {
+			var userAgent string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				userAgent = r.Header.Get("User-Agent")
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			res, err := NewHttpClient("sometoken", append(tt.opts, WithBaseURL(server.URL))...).GetRules()
+			if err != nil {
+				t.Fatalf("got err %v", err)
+			}
+			res.Body.Close()
+
+			if userAgent != tt.want {
+				t.Errorf("got %q, want %q", userAgent, tt.want)
+			}
+		})
+	}
+}
+
+func TestCloseIdleConnectionsDialsANewConnection(t *testing.T) {
+	var connections int
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			connections++
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	instance := NewHttpClient("sometoken", WithBaseURL(server.URL))
+	for i := 0; i < 2; i++ {
+		res, err := instance.GetSearchStream(nil)
+		if err != nil {
+			t.Fatalf("got err %v", err)
+		}
+		io.Copy(io.Discard, res.Body)
+		res.Body.Close()
+		instance.(*httpClient).CloseIdleConnections()
+	}
+
+	if connections != 2 {
+		t.Errorf("got %d connections, want 2", connections)
+	}
+}
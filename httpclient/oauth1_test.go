@@ -0,0 +1,64 @@
+package httpclient
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOAuth1SignatureMatchesTwitterExample(t *testing.T) {
+	// the example from https://developer.twitter.com/en/docs/authentication/oauth-1-0a/creating-a-signature
+	signer := &oauth1Signer{
+		credentials: OAuth1Credentials{
+			ConsumerKey:       "xvz1evFS4wEEPTGEFPHBog",
+			ConsumerSecret:    "kAcSOqF21Fu85e7zjz7ZN2U4ZRhfV3WpwPAoE3Z7kBw",
+			AccessToken:       "370773112-GmHxMAgYyLbNEtIKZeRNFsMKPR9EyMZeS9weJAEb",
+			AccessTokenSecret: "LswwdoUaIvS8ltyTt5jkRh4J50vUPVVHtR2YPi5kE",
+		},
+		now:   func() time.Time { return time.Unix(1318622958, 0) },
+		nonce: func() string { return "kYjzVBB8Y0ZFabxSWbWovY3uYSQ2pTgmZeNu2VS4cg" },
+	}
+	requestUrl, _ := url.Parse("https://api.twitter.com/1.1/statuses/update.json?include_entities=true")
+	form := url.Values{"status": []string{"Hello Ladies + Gentlemen, a signed OAuth request!"}}
+
+	header := signer.authorization("post", requestUrl, form)
+
+	if !strings.Contains(header, `oauth_signature="hCtSmYh%2BiHYCEqBWrE7C7hYmtUk%3D"`) {
+		t.Errorf("got %s, want signature hCtSmYh+iHYCEqBWrE7C7hYmtUk=", header)
+	}
+
+	if !strings.HasPrefix(header, "OAuth ") || !strings.Contains(header, `oauth_token="370773112-GmHxMAgYyLbNEtIKZeRNFsMKPR9EyMZeS9weJAEb"`) {
+		t.Errorf("got %s, want an OAuth header with the access token", header)
+	}
+}
+
+func TestWithOAuth1SignsRequestsInsteadOfBearer(t *testing.T) {
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	instance := NewHttpClient("sometoken", WithBaseURL(server.URL), WithOAuth1(OAuth1Credentials{ConsumerKey: "key"}))
+	res, err := instance.GetRules()
+	if err != nil {
+		t.Fatalf("got err %v", err)
+	}
+	res.Body.Close()
+
+	if !strings.HasPrefix(auth, "OAuth ") || !strings.Contains(auth, `oauth_consumer_key="key"`) {
+		t.Errorf("got %s, want an OAuth header", auth)
+	}
+
+	if !instance.(*httpClient).UserContext() {
+		t.Errorf("expected the client to have user context")
+	}
+
+	if NewHttpClient("sometoken").(*httpClient).UserContext() {
+		t.Errorf("expected a bearer token client not to have user context")
+	}
+}
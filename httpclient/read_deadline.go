@@ -0,0 +1,49 @@
+package httpclient
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"time"
+)
+
+// WithReadDeadline sets a read deadline on the streaming connection that is extended before every read,
+// so a half-open connection fails at the TCP layer once nothing, not even a keep-alive, arrived for `d`.
+// Twitter sends a keep-alive every 20 seconds, so `d` should be comfortably longer than that.
+// The read then fails with a timeout, which ends the stream or, with `stream.WithAutoReconnect`, reconnects it.
+// Rules and token requests are not affected. Defaults to 0, which means no deadline.
+func WithReadDeadline(d time.Duration) Option {
+	return func(t *httpClient) {
+		t.readDeadline = d
+	}
+}
+
+// deadlineConn is a net.Conn that extends its read deadline before every Read.
+type deadlineConn struct {
+	net.Conn
+	timeout time.Duration
+}
+
+func (c *deadlineConn) Read(b []byte) (int, error) {
+	if err := c.Conn.SetReadDeadline(time.Now().Add(c.timeout)); err != nil {
+		return 0, err
+	}
+	return c.Conn.Read(b)
+}
+
+// withReadDeadline wraps the connections dialed by the transport in a deadlineConn.
+func withReadDeadline(transport *http.Transport, timeout time.Duration) *http.Transport {
+	dial := transport.DialContext
+	if dial == nil {
+		dial = (&net.Dialer{}).DialContext
+	}
+
+	transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := dial(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+		return &deadlineConn{Conn: conn, timeout: timeout}, nil
+	}
+	return transport
+}
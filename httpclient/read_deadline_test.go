@@ -0,0 +1,46 @@
+package httpclient
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWithReadDeadlineFailsStalledStream(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		for i := 0; i < 3; i++ {
+			// keep-alives arrive faster than the deadline
+			w.Write([]byte("\r\n"))
+			w.(http.Flusher).Flush()
+			time.Sleep(20 * time.Millisecond)
+		}
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	instance := NewHttpClient("sometoken", WithBaseURL(server.URL), WithReadDeadline(50*time.Millisecond))
+
+	res, err := instance.GetSearchStream(nil)
+	if err != nil {
+		t.Fatalf("got err %v", err)
+	}
+	defer res.Body.Close()
+
+	reader := bufio.NewReader(res.Body)
+	for i := 0; i < 3; i++ {
+		if _, err := reader.ReadString('\n'); err != nil {
+			t.Fatalf("got err %v reading keep-alive %d", err, i)
+		}
+	}
+
+	_, err = reader.ReadString('\n')
+	if netErr, ok := err.(net.Error); !ok || !netErr.Timeout() {
+		t.Errorf("got %v, want a timeout", err)
+	}
+}
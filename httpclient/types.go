@@ -1,6 +1,8 @@
 package httpclient
 
 type RequestOpts struct {
+	// Stream marks a long-lived streaming request, which request timeouts don't apply to.
+	Stream  bool
 	Retries uint8
 	Method  string
 	Url     string
@@ -0,0 +1,84 @@
+// Package metrics exports the counters of a stream to Prometheus.
+// It is a separate module, so the core module stays free of the Prometheus dependency.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"dev.freespoke.com/twitter-stream/stream"
+)
+
+// DefaultNamespace prefixes the metric names unless another namespace is given.
+const DefaultNamespace = "twitter_stream"
+
+type (
+	// StatsSource is what the collector reads counters from. stream.IStream implements it.
+	StatsSource interface {
+		Stats() stream.Stats
+	}
+
+	// Collector is a prometheus.Collector that reads the Stats of a stream every time it is scraped,
+	// so the counters never drift from Stats and cost nothing between scrapes.
+	Collector struct {
+		source         StatsSource
+		messages       *prometheus.Desc
+		bytes          *prometheus.Desc
+		bytesPerSecond *prometheus.Desc
+		reconnects     *prometheus.Desc
+		dropped        *prometheus.Desc
+		decodeErrors   *prometheus.Desc
+		rateLimited    *prometheus.Desc
+	}
+)
+
+// NewCollector creates a Collector for the stream, with metric names prefixed by `namespace`,
+// or by DefaultNamespace if it is empty. `labels` are added to every metric, e.g. to tell streams apart.
+func NewCollector(source StatsSource, namespace string, labels prometheus.Labels) *Collector {
+	if namespace == "" {
+		namespace = DefaultNamespace
+	}
+	desc := func(name string, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "", name), help, nil, labels)
+	}
+
+	return &Collector{
+		source:         source,
+		messages:       desc("messages_delivered_total", "Messages handed to the consumer."),
+		bytes:          desc("read_bytes_total", "Bytes read from Twitter."),
+		bytesPerSecond: desc("read_bytes_per_second", "Average bytes read per second over the last 10 seconds."),
+		reconnects:     desc("reconnects_total", "Successful reconnects."),
+		dropped:        desc("messages_dropped_total", "Messages discarded by the overflow policy."),
+		decodeErrors:   desc("decode_errors_total", "Messages the unmarshal hook failed to decode."),
+		rateLimited:    desc("rate_limited_total", "Connection attempts rejected for rate limiting."),
+	}
+}
+
+// Register registers a Collector for the stream with `registerer`, such as a *prometheus.Registry,
+// using DefaultNamespace.
+func Register(registerer prometheus.Registerer, source StatsSource, labels prometheus.Labels) error {
+	return registerer.Register(NewCollector(source, DefaultNamespace, labels))
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.messages
+	ch <- c.bytes
+	ch <- c.bytesPerSecond
+	ch <- c.reconnects
+	ch <- c.dropped
+	ch <- c.decodeErrors
+	ch <- c.rateLimited
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.source.Stats()
+
+	ch <- prometheus.MustNewConstMetric(c.messages, prometheus.CounterValue, float64(stats.Delivered))
+	ch <- prometheus.MustNewConstMetric(c.bytes, prometheus.CounterValue, float64(stats.Bytes))
+	ch <- prometheus.MustNewConstMetric(c.bytesPerSecond, prometheus.GaugeValue, stats.BytesPerSecond)
+	ch <- prometheus.MustNewConstMetric(c.reconnects, prometheus.CounterValue, float64(stats.Reconnects))
+	ch <- prometheus.MustNewConstMetric(c.dropped, prometheus.CounterValue, float64(stats.Dropped))
+	ch <- prometheus.MustNewConstMetric(c.decodeErrors, prometheus.CounterValue, float64(stats.DecodeErrors))
+	ch <- prometheus.MustNewConstMetric(c.rateLimited, prometheus.CounterValue, float64(stats.RateLimited))
+}
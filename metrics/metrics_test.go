@@ -0,0 +1,72 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"dev.freespoke.com/twitter-stream/stream"
+)
+
+// statsMock returns fixed stats.
+type statsMock stream.Stats
+
+func (s statsMock) Stats() stream.Stats {
+	return stream.Stats(s)
+}
+
+func TestRegisterExportsStats(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	source := statsMock{Delivered: 5, Bytes: 1024, BytesPerSecond: 12.5, Reconnects: 2, Dropped: 1, DecodeErrors: 3, RateLimited: 4}
+
+	if err := Register(registry, source, prometheus.Labels{"stream": "main"}); err != nil {
+		t.Fatalf("got err %v", err)
+	}
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("got err %v", err)
+	}
+
+	want := map[string]float64{
+		"twitter_stream_messages_delivered_total": 5,
+		"twitter_stream_read_bytes_total":         1024,
+		"twitter_stream_read_bytes_per_second":    12.5,
+		"twitter_stream_reconnects_total":         2,
+		"twitter_stream_messages_dropped_total":   1,
+		"twitter_stream_decode_errors_total":      3,
+		"twitter_stream_rate_limited_total":       4,
+	}
+	if len(families) != len(want) {
+		t.Errorf("got %d metrics, want %d", len(families), len(want))
+	}
+
+	for _, family := range families {
+		metric := family.GetMetric()[0]
+		value := metric.GetCounter().GetValue()
+		if family.GetType().String() == "GAUGE" {
+			value = metric.GetGauge().GetValue()
+		}
+
+		if expected, ok := want[family.GetName()]; !ok || value != expected {
+			t.Errorf("got %s = %v, want %v", family.GetName(), value, expected)
+		}
+		if label := metric.GetLabel()[0]; label.GetName() != "stream" || label.GetValue() != "main" {
+			t.Errorf("got label %v on %s, want stream=main", label, family.GetName())
+		}
+	}
+}
+
+func TestNewCollectorUsesTheNamespace(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(NewCollector(statsMock{}, "ingest", nil))
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("got err %v", err)
+	}
+
+	if name := families[0].GetName(); name[:len("ingest_")] != "ingest_" {
+		t.Errorf("got %s, want the ingest namespace", name)
+	}
+}
@@ -0,0 +1,43 @@
+package rules
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// autoTagLength is the number of hex characters of the sha256 of the value used as an automatic tag.
+const autoTagLength = 8
+
+// WithAutoTagFromValue makes Create tag every rule that has no tag, or an empty one, with AutoTag of its value,
+// so every rule Get returns has a stable identifier. SetRules tags the desired rules the same way before comparing
+// them with the current ones, so untagged rules keep matching the rules it created.
+func WithAutoTagFromValue(enabled bool) Option {
+	return func(t *rules) {
+		t.autoTag = enabled
+	}
+}
+
+// AutoTag returns the tag WithAutoTagFromValue gives a rule: the first 8 hex characters of the sha256 of its value,
+// e.g. "cat has:images" is tagged "a81b648e". The same value always gets the same tag, and 8 characters make
+// a collision between the rules of an app unlikely, not impossible.
+func AutoTag(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])[:autoTagLength]
+}
+
+// autoTagged returns a copy of the request where every rule without a tag is tagged with AutoTag.
+func (t *rules) autoTagged(req CreateRulesRequest) CreateRulesRequest {
+	if !t.autoTag {
+		return req
+	}
+
+	tagged := CreateRulesRequest{Add: make([]*RuleValue, 0, len(req.Add))}
+	for _, rule := range req.Add {
+		if rule != nil && rule.Value != nil && (rule.Tag == nil || *rule.Tag == "") {
+			tag := AutoTag(*rule.Value)
+			rule = &RuleValue{Value: rule.Value, Tag: &tag}
+		}
+		tagged.Add = append(tagged.Add, rule)
+	}
+	return tagged
+}
@@ -0,0 +1,55 @@
+package rules
+
+import "testing"
+
+func TestAutoTagFromValue(t *testing.T) {
+	if got := AutoTag("cat has:images"); got != "a81b648e" {
+		t.Errorf("got %q, want the first 8 hex characters of the sha256", got)
+	}
+
+	t.Run("tags untagged rules on create", func(t *testing.T) {
+		var requests []string
+		instance := NewRules(givenRulesClient(`{"meta": {"sent": "today"}}`, &requests), WithAutoTagFromValue(true))
+		dog := "dog"
+		req := NewRuleBuilder().AddRule("cat has:images", "").Build()
+		req.Add = append(req.Add, &RuleValue{Value: &dog}, newRuleValue().setValueTag("bird", "birds"))
+
+		if _, err := instance.Create(req, false); err != nil {
+			t.Fatalf("got err %v", err)
+		}
+
+		want := `{"add":[{"value":"cat has:images","tag":"a81b648e"},{"value":"dog","tag":"` + AutoTag("dog") + `"},{"value":"bird","tag":"birds"}]}`
+		if len(requests) != 1 || requests[0] != want {
+			t.Errorf("got %v, want %s", requests, want)
+		}
+		if *req.Add[0].Tag != "" || req.Add[1].Tag != nil {
+			t.Errorf("got %+v, want the request left alone", req.Add)
+		}
+	})
+
+	t.Run("set rules matches the rules it tagged", func(t *testing.T) {
+		current := `{"data": [{"value": "cat has:images", "tag": "a81b648e", "id": "1"}], "meta": {"sent": "today"}}`
+		var requests []string
+		instance := NewRules(givenRulesClient(current, &requests), WithAutoTagFromValue(true))
+
+		if _, err := instance.SetRules(NewRuleBuilder().AddRule("cat has:images", "").Build(), false); err != nil {
+			t.Fatalf("got err %v", err)
+		}
+		if len(requests) != 0 {
+			t.Errorf("got %v, want the rule to match", requests)
+		}
+	})
+
+	t.Run("off by default", func(t *testing.T) {
+		var requests []string
+		instance := NewRules(givenRulesClient(`{"meta": {"sent": "today"}}`, &requests), WithAutoTagFromValue(false))
+
+		dog := "dog"
+		if _, err := instance.Create(CreateRulesRequest{Add: []*RuleValue{{Value: &dog}}}, false); err != nil {
+			t.Fatalf("got err %v", err)
+		}
+		if len(requests) != 1 || requests[0] != `{"add":[{"value":"dog"}]}` {
+			t.Errorf("got %v, want no tag", requests)
+		}
+	})
+}
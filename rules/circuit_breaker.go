@@ -0,0 +1,119 @@
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"dev.freespoke.com/twitter-stream/httpclient"
+)
+
+// ErrCircuitOpen is returned without making a request while the circuit breaker of WithCircuitBreaker is open.
+// Use errors.Is to detect it.
+var ErrCircuitOpen = errors.New("rules circuit breaker is open")
+
+// WithCircuitBreaker stops calling the rules endpoint after `failures` consecutive failed requests, returning
+// ErrCircuitOpen right away instead, until `cooldown` has elapsed. Then a single request is let through to test
+// the endpoint: if it succeeds, requests are made again, otherwise the breaker stays open for another cooldown.
+// Failures are 5xx responses, rate limits, and network errors. Rate limits only reach the breaker when
+// `httpclient.WithRateLimitRetries` is used, as the client retries them forever otherwise. Other errors,
+// such as invalid rules, show the endpoint is up and reset the count. Disabled by default.
+func WithCircuitBreaker(failures int, cooldown time.Duration) Option {
+	return func(t *rules) {
+		if failures > 0 {
+			t.breaker = &circuitBreaker{threshold: failures, cooldown: cooldown, now: time.Now}
+		}
+	}
+}
+
+// circuitBreaker counts consecutive failures of the rules endpoint. It is open once they reach the threshold.
+type circuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	failures  int
+	openedAt  time.Time
+	testing   bool
+	now       func() time.Time
+}
+
+// allow returns ErrCircuitOpen if a request can't be made now. Once the cooldown has elapsed,
+// it allows a single request to test the endpoint.
+func (b *circuitBreaker) allow() error {
+	if b == nil {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failures < b.threshold {
+		return nil
+	}
+	if b.testing {
+		return fmt.Errorf("%w: testing the endpoint", ErrCircuitOpen)
+	}
+	if wait := b.cooldown - b.now().Sub(b.openedAt); wait > 0 {
+		return fmt.Errorf("%w: retry in %v", ErrCircuitOpen, wait)
+	}
+	b.testing = true
+	return nil
+}
+
+// record counts the outcome of a request that was allowed.
+func (b *circuitBreaker) record(err error) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.testing = false
+	if !isEndpointFailure(err) {
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.failures >= b.threshold {
+		b.openedAt = b.now()
+	}
+}
+
+// isEndpointFailure tells whether the error means the rules endpoint is failing, rather than rejecting the request.
+func isEndpointFailure(err error) bool {
+	var rateLimit *httpclient.RateLimitError
+	var status *httpclient.StatusError
+	var network *url.Error
+	switch {
+	case err == nil:
+		return false
+	case errors.As(err, &rateLimit), errors.As(err, &network):
+		return true
+	case errors.As(err, &status):
+		return status.StatusCode >= 500
+	default:
+		return false
+	}
+}
+
+// addRules makes an AddRules request unless the circuit breaker is open.
+func (t *rules) addRules(queryParams *url.Values, body string) (*http.Response, error) {
+	if err := t.breaker.allow(); err != nil {
+		return nil, err
+	}
+	res, err := t.httpClient.AddRules(queryParams, body)
+	t.breaker.record(err)
+	return res, err
+}
+
+// getRules makes a GetRules request unless the circuit breaker is open.
+func (t *rules) getRules() (*http.Response, error) {
+	if err := t.breaker.allow(); err != nil {
+		return nil, err
+	}
+	res, err := t.httpClient.GetRules()
+	t.breaker.record(err)
+	return res, err
+}
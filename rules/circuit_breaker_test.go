@@ -0,0 +1,82 @@
+package rules
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"dev.freespoke.com/twitter-stream/httpclient"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	var calls int
+	var failing = true
+	mockClient := httpclient.NewHttpClientMock("sometoken")
+	mockClient.MockGetRules = func() (*http.Response, error) {
+		calls++
+		if failing {
+			return nil, &httpclient.StatusError{StatusCode: http.StatusServiceUnavailable, Message: "unavailable"}
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"meta": {"sent": "today"}}`)),
+		}, nil
+	}
+
+	now := time.Now()
+	instance := NewRules(mockClient, WithCircuitBreaker(2, time.Minute)).(*rules)
+	instance.breaker.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		if _, err := instance.Get(); errors.Is(err, ErrCircuitOpen) || err == nil {
+			t.Fatalf("got err %v on call %d, want the request made", err, i)
+		}
+	}
+
+	if _, err := instance.Get(); !errors.Is(err, ErrCircuitOpen) || calls != 2 {
+		t.Fatalf("got err %v after %d calls, want %v without a request", err, calls, ErrCircuitOpen)
+	}
+
+	// the test request after the cooldown fails, so the breaker opens again
+	now = now.Add(time.Minute)
+	if _, err := instance.Get(); errors.Is(err, ErrCircuitOpen) || calls != 3 {
+		t.Fatalf("got err %v after %d calls, want a test request", err, calls)
+	}
+	if _, err := instance.Get(); !errors.Is(err, ErrCircuitOpen) || calls != 3 {
+		t.Fatalf("got err %v after %d calls, want %v", err, calls, ErrCircuitOpen)
+	}
+
+	// the next test request succeeds, so the breaker closes
+	now = now.Add(time.Minute)
+	failing = false
+	for i := 0; i < 2; i++ {
+		if _, err := instance.Get(); err != nil {
+			t.Fatalf("got err %v, want the breaker closed", err)
+		}
+	}
+	if calls != 5 {
+		t.Errorf("got %d calls, want 5", calls)
+	}
+}
+
+func TestCircuitBreakerIgnoresRejectedRequests(t *testing.T) {
+	var calls int
+	mockClient := httpclient.NewHttpClientMock("sometoken")
+	mockClient.MockGetRules = func() (*http.Response, error) {
+		calls++
+		return nil, &httpclient.StatusError{StatusCode: http.StatusBadRequest, Message: "invalid"}
+	}
+
+	instance := NewRules(mockClient, WithCircuitBreaker(1, time.Minute))
+	for i := 0; i < 3; i++ {
+		if _, err := instance.Get(); errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("got err %v, want 4xx responses not to open the breaker", err)
+		}
+	}
+	if calls != 3 {
+		t.Errorf("got %d calls, want 3", calls)
+	}
+}
@@ -0,0 +1,31 @@
+package rules
+
+import "time"
+
+// WithClockSkewWarning logs a warning when the "sent" time of a rules response differs from the local clock by
+// more than `threshold`, which reveals clock drift that can break time-sensitive logic, such as backfill windows.
+// The difference includes the time the response took to arrive, so keep the threshold well above it, e.g. 30s.
+// Disabled by default.
+func WithClockSkewWarning(threshold time.Duration) Option {
+	return func(t *rules) {
+		t.skewThreshold = threshold
+	}
+}
+
+// checkClockSkew warns about clock skew if the response was sent too far from the local time.
+// Responses without a valid "sent" time are ignored.
+func (t *rules) checkClockSkew(res *TwitterRuleResponse) {
+	if t.skewThreshold <= 0 || res == nil || res.Meta.Sent == "" {
+		return
+	}
+	sent, err := time.Parse(time.RFC3339Nano, res.Meta.Sent)
+	if err != nil {
+		return
+	}
+
+	skew := t.now().Sub(sent)
+	if skew > t.skewThreshold || skew < -t.skewThreshold {
+		t.logf("Local clock differs from twitter's by %v, which is more than %v. Check the clock of this machine.",
+			skew.Round(time.Millisecond), t.skewThreshold)
+	}
+}
@@ -0,0 +1,49 @@
+package rules
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"dev.freespoke.com/twitter-stream/httpclient"
+)
+
+func TestClockSkewWarning(t *testing.T) {
+	now := time.Date(2022, 1, 1, 12, 0, 0, 0, time.UTC)
+	var tests = []struct {
+		sent string
+		warn bool
+	}{
+		{"2022-01-01T12:00:10.000Z", false},
+		{"2022-01-01T12:01:00.000Z", true},
+		{"2022-01-01T11:59:00Z", true},
+		{"today", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sent, func(t *testing.T) {
+			mockClient := httpclient.NewHttpClientMock("sometoken")
+			mockClient.MockGetRules = func() (*http.Response, error) {
+				body := fmt.Sprintf(`{"meta": {"sent": %q}}`, tt.sent)
+				return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(body))}, nil
+			}
+			instance := NewRules(mockClient, WithClockSkewWarning(30*time.Second)).(*rules)
+			instance.now = func() time.Time { return now }
+			var warnings []string
+			instance.logf = func(format string, args ...interface{}) {
+				warnings = append(warnings, fmt.Sprintf(format, args...))
+			}
+
+			if _, err := instance.Get(); err != nil {
+				t.Fatalf("got err %v", err)
+			}
+
+			if (len(warnings) > 0) != tt.warn {
+				t.Errorf("got warnings %v, want a warning: %v", warnings, tt.warn)
+			}
+		})
+	}
+}
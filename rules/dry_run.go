@@ -0,0 +1,47 @@
+package rules
+
+import "strings"
+
+// RuleVerdict is the result of validating a single submitted rule with a dry-run request.
+type RuleVerdict struct {
+	Value  string
+	Tag    string
+	Valid  bool
+	Reason string
+}
+
+// Verdicts correlates a dry-run response with the rules that were submitted in `req`.
+// Each submitted rule is matched to the Errors array by its Value. A rule without a matching
+// error is considered valid. Reason contains the error title and any details Twitter returned.
+func (r *TwitterRuleResponse) Verdicts(req CreateRulesRequest) []RuleVerdict {
+	errorsByValue := make(map[string]ErrorRule, len(r.Errors))
+	for _, e := range r.Errors {
+		errorsByValue[e.Value] = e
+	}
+
+	verdicts := make([]RuleVerdict, 0, len(req.Add))
+	for _, rule := range req.Add {
+		verdict := RuleVerdict{Valid: true}
+		if rule.Value != nil {
+			verdict.Value = *rule.Value
+		}
+		if rule.Tag != nil {
+			verdict.Tag = *rule.Tag
+		}
+
+		if e, ok := errorsByValue[verdict.Value]; ok {
+			verdict.Valid = false
+			verdict.Reason = e.reason()
+		}
+		verdicts = append(verdicts, verdict)
+	}
+
+	return verdicts
+}
+
+func (e ErrorRule) reason() string {
+	if len(e.Details) == 0 {
+		return e.Title
+	}
+	return e.Title + ": " + strings.Join(e.Details, "; ")
+}
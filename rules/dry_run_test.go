@@ -0,0 +1,46 @@
+package rules
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestVerdictsCorrelatesErrorsByValue(t *testing.T) {
+	req := NewRuleBuilder().
+		AddRule("cat has:images", "cats").
+		AddRule("dog has:nonsense", "dogs").
+		Build()
+
+	payload := `{
+		"meta": {"sent": "today", "summary": {"created": 1, "not_created": 1}},
+		"errors": [{
+			"value": "dog has:nonsense",
+			"title": "UnprocessableEntity",
+			"type": "https://api.twitter.com/2/problems/invalid-rules",
+			"details": ["Reference to invalid operator 'has:nonsense'"]
+		}]
+	}`
+	res := new(TwitterRuleResponse)
+	if err := json.Unmarshal([]byte(payload), res); err != nil {
+		t.Fatal(err)
+	}
+
+	verdicts := res.Verdicts(req)
+
+	if len(verdicts) != 2 {
+		t.Fatalf("got %d verdicts, want 2", len(verdicts))
+	}
+
+	if !verdicts[0].Valid || verdicts[0].Value != "cat has:images" || verdicts[0].Tag != "cats" {
+		t.Errorf("got %+v, want valid cat rule", verdicts[0])
+	}
+
+	if verdicts[1].Valid {
+		t.Errorf("got %+v, want invalid dog rule", verdicts[1])
+	}
+
+	expected := "UnprocessableEntity: Reference to invalid operator 'has:nonsense'"
+	if verdicts[1].Reason != expected {
+		t.Errorf("got %s, want %s", verdicts[1].Reason, expected)
+	}
+}
@@ -0,0 +1,101 @@
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrOutsideNamespace is returned by Delete when a rule to delete isn't in the namespace of WithRuleNamespace.
+// Nothing is deleted. Use errors.Is to detect it.
+var ErrOutsideNamespace = errors.New("rule is outside the namespace")
+
+// WithRuleNamespace scopes the rules managed through this instance to the rules whose tag starts with `prefix`,
+// e.g. "teamA:", so several teams can share the rules of one app. Created rules get their tag prefixed,
+// Get and every method built on it, such as SetRules and DeleteAllRules, only see the rules of the namespace,
+// and Delete refuses rules outside of it. Tags are returned without the prefix, so they round-trip.
+// It is advisory: the rules still belong to the app, anyone with the credentials can change them, and the
+// stream delivers the tweets of every rule, so use the tags of "matching_rules" to tell the namespaces apart.
+func WithRuleNamespace(prefix string) Option {
+	return func(t *rules) {
+		t.namespace = prefix
+	}
+}
+
+// namespaced returns a copy of the request with the namespace prefixed to every tag.
+func (t *rules) namespaced(req CreateRulesRequest) CreateRulesRequest {
+	if t.namespace == "" {
+		return req
+	}
+
+	prefixed := CreateRulesRequest{Add: make([]*RuleValue, 0, len(req.Add))}
+	for _, rule := range req.Add {
+		tag := t.namespace
+		if rule.Tag != nil {
+			tag += *rule.Tag
+		}
+		prefixed.Add = append(prefixed.Add, &RuleValue{Value: rule.Value, Tag: &tag})
+	}
+	return prefixed
+}
+
+// scope removes the rules outside the namespace from the response, and the prefix from the tags of the others.
+func (t *rules) scope(res *TwitterRuleResponse) {
+	if t.namespace == "" || res == nil {
+		return
+	}
+
+	scoped := res.Data[:0]
+	for _, rule := range res.Data {
+		if strings.HasPrefix(rule.Tag, t.namespace) {
+			scoped = append(scoped, rule)
+		}
+	}
+	res.Data = scoped
+	t.unscope(res)
+}
+
+// unscope removes the namespace prefix from the tags of the response.
+func (t *rules) unscope(res *TwitterRuleResponse) {
+	if t.namespace == "" || res == nil {
+		return
+	}
+	for i := range res.Data {
+		res.Data[i].Tag = strings.TrimPrefix(res.Data[i].Tag, t.namespace)
+	}
+}
+
+// checkNamespace returns ErrOutsideNamespace if a rule of the request isn't in the namespace.
+func (t *rules) checkNamespace(req DeleteRulesRequest) error {
+	if t.namespace == "" {
+		return nil
+	}
+
+	current, err := t.Get()
+	if err != nil {
+		return err
+	}
+	inside := make(map[string]bool, 2*len(current.Data))
+	for _, rule := range current.Data {
+		inside["id "+rule.Id] = true
+		inside["value "+rule.Value] = true
+	}
+
+	var outside []string
+	for _, id := range req.Delete.Ids {
+		if !inside["id "+strconv.Itoa(id)] {
+			outside = append(outside, "id "+strconv.Itoa(id))
+		}
+	}
+	for _, value := range req.Delete.Values {
+		if !inside["value "+value] {
+			outside = append(outside, fmt.Sprintf("value %q", value))
+		}
+	}
+
+	if len(outside) > 0 {
+		return fmt.Errorf("%w %q: %s", ErrOutsideNamespace, t.namespace, strings.Join(outside, ", "))
+	}
+	return nil
+}
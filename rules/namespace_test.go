@@ -0,0 +1,69 @@
+package rules
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRuleNamespace(t *testing.T) {
+	current := `{"data": [
+		{"value": "cat", "tag": "teamA:cats", "id": "1"},
+		{"value": "dog", "tag": "teamB:dogs", "id": "2"}
+	], "meta": {"sent": "today"}}`
+
+	t.Run("gets the rules of the namespace", func(t *testing.T) {
+		var requests []string
+		instance := NewRules(givenRulesClient(current, &requests), WithRuleNamespace("teamA:"))
+
+		res, err := instance.Get()
+
+		if err != nil || len(res.Data) != 1 || res.Data[0].Tag != "cats" || res.Data[0].Id != "1" {
+			t.Errorf("got %+v, %v, want only cats without the prefix", res, err)
+		}
+	})
+
+	t.Run("creates rules in the namespace", func(t *testing.T) {
+		var requests []string
+		instance := NewRules(givenRulesClient(current, &requests), WithRuleNamespace("teamA:"))
+		req := NewRuleBuilder().AddRule("bird", "birds").Build()
+
+		if _, err := instance.Create(req, false); err != nil {
+			t.Fatalf("got err %v", err)
+		}
+
+		if len(requests) != 1 || requests[0] != `{"add":[{"value":"bird","tag":"teamA:birds"}]}` {
+			t.Errorf("got %v, want the tag prefixed", requests)
+		}
+		if *req.Add[0].Tag != "birds" {
+			t.Errorf("got tag %q, want the request left alone", *req.Add[0].Tag)
+		}
+	})
+
+	t.Run("refuses to delete rules outside the namespace", func(t *testing.T) {
+		var requests []string
+		instance := NewRules(givenRulesClient(current, &requests), WithRuleNamespace("teamA:"))
+
+		if _, err := instance.Delete(NewDeleteRulesRequest(1, 2), false); !errors.Is(err, ErrOutsideNamespace) {
+			t.Errorf("got err %v, want %v", err, ErrOutsideNamespace)
+		}
+		if _, err := instance.Delete(NewDeleteRulesRequestByValue("dog"), false); !errors.Is(err, ErrOutsideNamespace) {
+			t.Errorf("got err %v, want %v", err, ErrOutsideNamespace)
+		}
+		if len(requests) != 0 {
+			t.Errorf("got %v, want nothing deleted", requests)
+		}
+	})
+
+	t.Run("deletes every rule of the namespace", func(t *testing.T) {
+		var requests []string
+		instance := NewRules(givenRulesClient(current, &requests), WithRuleNamespace("teamA:"))
+
+		if _, err := instance.DeleteAllRules(false); err != nil {
+			t.Fatalf("got err %v", err)
+		}
+
+		if len(requests) != 1 || requests[0] != `{"delete":{"ids":[1]}}` {
+			t.Errorf("got %v, want only the rule of the namespace deleted", requests)
+		}
+	})
+}
@@ -0,0 +1,117 @@
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// duplicateRuleTitle is the error title Twitter uses when a rule with the same value already exists.
+const duplicateRuleTitle = "DuplicateRule"
+
+var (
+	// ErrRuleNotFound is returned by RetagRule when no current rule has the id.
+	ErrRuleNotFound = errors.New("rule not found")
+	// ErrRuleNotCreated is returned by RetagRule when Twitter did not create the retagged rule.
+	// The old rule is kept.
+	ErrRuleNotCreated = errors.New("retagged rule was not created")
+)
+
+// RetagRule changes the tag of the rule with `id`, keeping its value. Twitter can't update a rule in place,
+// so the rule is created with the new tag first, and the old rule is only deleted once that succeeded,
+// so the value is never left untracked.
+//
+// Twitter rejects a rule whose value already exists as a DuplicateRule, whatever its tag. When it does, the old
+// rule is deleted before the new one is created instead, and recreated with its old tag if that fails.
+// The returned response aggregates every create and delete response.
+func (t *rules) RetagRule(id string, newTag string, dryRun bool) (*TwitterRuleResponse, error) {
+	current, err := t.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	var old *DataRule
+	for i := range current.Data {
+		if current.Data[i].Id == id {
+			old = &current.Data[i]
+			break
+		}
+	}
+	if old == nil {
+		return nil, fmt.Errorf("%w: %s", ErrRuleNotFound, id)
+	}
+
+	aggregated := new(TwitterRuleResponse)
+	if old.Tag == newTag {
+		return aggregated, nil
+	}
+
+	numericID, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, err
+	}
+	retagged := NewRuleBuilder().AddRule(old.Value, newTag).Build()
+	deleteOld := NewDeleteRulesRequest(numericID)
+
+	created, err := t.Create(retagged, dryRun)
+	if err != nil {
+		return aggregated, err
+	}
+	aggregated.merge(created)
+
+	if created.Meta.Summary.Created == 0 {
+		if !isDuplicate(created) {
+			return aggregated, fmt.Errorf("%w: %s", ErrRuleNotCreated, old.Value)
+		}
+		return t.deleteThenCreate(aggregated, deleteOld, retagged, *old, dryRun)
+	}
+
+	deleted, err := t.Delete(deleteOld, dryRun)
+	if err != nil {
+		return aggregated, err
+	}
+	aggregated.merge(deleted)
+
+	return aggregated, nil
+}
+
+// deleteThenCreate retags a rule whose value Twitter doesn't allow twice, restoring the old rule if the
+// retagged one can't be created.
+func (t *rules) deleteThenCreate(aggregated *TwitterRuleResponse, deleteOld DeleteRulesRequest,
+	retagged CreateRulesRequest, old DataRule, dryRun bool) (*TwitterRuleResponse, error) {
+	aggregated.Errors = nil
+	aggregated.Meta.Summary.NotCreated = 0
+
+	deleted, err := t.Delete(deleteOld, dryRun)
+	if err != nil {
+		return aggregated, err
+	}
+	aggregated.merge(deleted)
+
+	created, err := t.Create(retagged, dryRun)
+	if err == nil && created.Meta.Summary.Created > 0 {
+		aggregated.merge(created)
+		return aggregated, nil
+	}
+	if err == nil {
+		aggregated.merge(created)
+		err = fmt.Errorf("%w: %s", ErrRuleNotCreated, old.Value)
+	}
+
+	restored, restoreErr := t.Create(NewRuleBuilder().AddRule(old.Value, old.Tag).Build(), dryRun)
+	if restoreErr != nil {
+		return aggregated, fmt.Errorf("%v, and the old rule could not be restored: %w", err, restoreErr)
+	}
+	aggregated.merge(restored)
+	return aggregated, err
+}
+
+// isDuplicate returns true if Twitter refused to create a rule because its value already exists.
+func isDuplicate(res *TwitterRuleResponse) bool {
+	for _, e := range res.Errors {
+		if e.Title == duplicateRuleTitle {
+			return true
+		}
+	}
+	return false
+}
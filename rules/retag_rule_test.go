@@ -0,0 +1,98 @@
+package rules
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"dev.freespoke.com/twitter-stream/httpclient"
+)
+
+const (
+	retagCreated   = `{"meta": {"summary": {"created": 1}}}`
+	retagDeleted   = `{"meta": {"summary": {"deleted": 1}}}`
+	retagDuplicate = `{"meta": {"summary": {"not_created": 1}}, "errors": [{"value": "cat", "id": "1", "title": "DuplicateRule"}]}`
+	retagInvalid   = `{"meta": {"summary": {"not_created": 1}}, "errors": [{"value": "cat", "title": "UnprocessableEntity"}]}`
+)
+
+// givenRetagClient returns a client with a single rule, answering rule changes with `responses` in order.
+func givenRetagClient(requests *[]string, responses ...string) httpclient.IHttpClient {
+	mockClient := httpclient.NewHttpClientMock("sometoken")
+	mockClient.MockGetRules = func() (*http.Response, error) {
+		body := `{"data": [{"value": "cat", "tag": "cats", "id": "1"}]}`
+		return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(body))}, nil
+	}
+	mockClient.MockAddRules = func(queryParams *url.Values, body string) (*http.Response, error) {
+		*requests = append(*requests, body)
+		res := responses[0]
+		responses = responses[1:]
+		return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(res))}, nil
+	}
+	return mockClient
+}
+
+func TestRetagRule(t *testing.T) {
+	var tests = []struct {
+		name      string
+		responses []string
+		requests  []string
+		err       error
+	}{
+		{
+			"creates before deleting",
+			[]string{retagCreated, retagDeleted},
+			[]string{`{"add":[{"value":"cat","tag":"pets"}]}`, `{"delete":{"ids":[1]}}`},
+			nil,
+		},
+		{
+			"deletes first when the value is a duplicate",
+			[]string{retagDuplicate, retagDeleted, retagCreated},
+			[]string{`{"add":[{"value":"cat","tag":"pets"}]}`, `{"delete":{"ids":[1]}}`, `{"add":[{"value":"cat","tag":"pets"}]}`},
+			nil,
+		},
+		{
+			"restores the old rule when the retagged one fails",
+			[]string{retagDuplicate, retagDeleted, retagInvalid, retagCreated},
+			[]string{`{"add":[{"value":"cat","tag":"pets"}]}`, `{"delete":{"ids":[1]}}`, `{"add":[{"value":"cat","tag":"pets"}]}`, `{"add":[{"value":"cat","tag":"cats"}]}`},
+			ErrRuleNotCreated,
+		},
+		{
+			"keeps the old rule when the retagged one is invalid",
+			[]string{retagInvalid},
+			[]string{`{"add":[{"value":"cat","tag":"pets"}]}`},
+			ErrRuleNotCreated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []string
+			instance := NewRules(givenRetagClient(&requests, tt.responses...))
+
+			_, err := instance.RetagRule("1", "pets", false)
+
+			if !errors.Is(err, tt.err) {
+				t.Errorf("got err %v, want %v", err, tt.err)
+			}
+			if strings.Join(requests, "\n") != strings.Join(tt.requests, "\n") {
+				t.Errorf("got requests\n%s\nwant\n%s", strings.Join(requests, "\n"), strings.Join(tt.requests, "\n"))
+			}
+		})
+	}
+}
+
+func TestRetagRuleWithUnknownId(t *testing.T) {
+	var requests []string
+	instance := NewRules(givenRetagClient(&requests))
+
+	if _, err := instance.RetagRule("2", "pets", false); !errors.Is(err, ErrRuleNotFound) {
+		t.Errorf("got err %v, want %v", err, ErrRuleNotFound)
+	}
+
+	if res, err := instance.RetagRule("1", "cats", false); err != nil || len(requests) != 0 || !res.IsEmpty() {
+		t.Errorf("got %v, %v and %d requests, want nothing to do for the same tag", res, err, len(requests))
+	}
+}
@@ -1,6 +1,11 @@
 package rules
 
-type  (
+import (
+	"encoding/json"
+	"strconv"
+)
+
+type (
 	// IRuleBuilder is an interface that describers how to implement a RuleBuilder.
 	IRuleBuilder interface {
 		AddRule(value string, tag string) *RuleBuilder
@@ -25,19 +30,43 @@ type  (
 	}
 
 	// DeleteRulesRequest is a struct used to create the payload for deleting rules.
+	// Rules are deleted either by their Ids or by their Values.
 	DeleteRulesRequest struct {
 		Delete struct {
-			Ids []int `json:"ids"`
+			Ids    []int    `json:"ids,omitempty"`
+			Values []string `json:"values,omitempty"`
 		} `json:"delete"`
 	}
-
 )
 
 // NewDeleteRulesRequest will create an instance of DeleteRulesRequest.
 func NewDeleteRulesRequest(ids ...int) DeleteRulesRequest {
-	return DeleteRulesRequest{Delete: struct {
-		Ids []int `json:"ids"`
-	}(struct{ Ids []int }{Ids: ids})}
+	req := DeleteRulesRequest{}
+	req.Delete.Ids = ids
+	return req
+}
+
+// NewDeleteRulesRequestByValue will create an instance of DeleteRulesRequest that deletes rules by their value.
+func NewDeleteRulesRequestByValue(values ...string) DeleteRulesRequest {
+	req := DeleteRulesRequest{}
+	req.Delete.Values = values
+	return req
+}
+
+// DeleteRequestFromResponse will create an instance of DeleteRulesRequest that deletes the rules of a response
+// for which `filter` returns true, such as the rules returned by `Get`. A nil filter deletes every rule.
+// Rules whose id isn't a number are skipped.
+func DeleteRequestFromResponse(resp *TwitterRuleResponse, filter func(DataRule) bool) DeleteRulesRequest {
+	ids := []int{}
+	for _, rule := range resp.Data {
+		if filter != nil && !filter(rule) {
+			continue
+		}
+		if id, err := strconv.ParseInt(rule.Id, 10, 64); err == nil {
+			ids = append(ids, int(id))
+		}
+	}
+	return NewDeleteRulesRequest(ids...)
 }
 
 // NewRuleBuilder will create an instance of `RuleBuilder`.
@@ -47,8 +76,8 @@ func NewRuleBuilder() *RuleBuilder {
 	}
 }
 
-//AddRule will create a rule to be build for filtered-stream.
-//Read more about rule limitations here https://developer.twitter.com/en/docs/twitter-api/tweets/filtered-stream/introduction.
+// AddRule will create a rule to be build for filtered-stream.
+// Read more about rule limitations here https://developer.twitter.com/en/docs/twitter-api/tweets/filtered-stream/introduction.
 func (r *RuleBuilder) AddRule(value string, tag string) *RuleBuilder {
 	rule := newRuleValue().setValueTag(value, tag)
 	r.rules = append(r.rules, rule)
@@ -72,3 +101,10 @@ func (r *RuleValue) setValueTag(value string, tag string) *RuleValue {
 	r.Tag = &tag
 	return r
 }
+
+// JSON returns the body Create sends for the request, to log it or replay it with curl when Twitter rejects
+// rules. Combine it with a dry run, which Create sends with the "dry_run=true" query param, to try changes
+// without touching your rules.
+func (r CreateRulesRequest) JSON() ([]byte, error) {
+	return json.Marshal(r)
+}
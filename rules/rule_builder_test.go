@@ -45,3 +45,49 @@ func TestNewRuleBuilderBuildsManyRules(t *testing.T) {
 		t.Errorf("Expected %v to equal %v", string(body), "{\"add\":[{\"value\":\"cats\",\"tag\":\"cat tweets\"},{\"value\":\"dogs\",\"tag\":\"dog tweets\"}]}")
 	}
 }
+
+func TestNewDeleteRulesRequestByValueMarshalsWell(t *testing.T) {
+	result := NewDeleteRulesRequestByValue("cats", "dogs")
+	body, err := json.Marshal(result)
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	if string(body) != "{\"delete\":{\"values\":[\"cats\",\"dogs\"]}}" {
+		t.Errorf("Expected %v to equal %v", string(body), "{\"delete\":{\"values\":[\"cats\",\"dogs\"]}}")
+	}
+}
+
+func TestDeleteRequestFromResponse(t *testing.T) {
+	res := &TwitterRuleResponse{Data: []DataRule{
+		{Value: "cat has:images", Tag: "cats", Id: "1"},
+		{Value: "dog", Tag: "dogs", Id: "2"},
+		{Value: "cat -is:retweet", Tag: "cats", Id: "3"},
+	}}
+
+	var tests = []struct {
+		name   string
+		filter func(DataRule) bool
+		ids    []int
+	}{
+		{"nil filter deletes all", nil, []int{1, 2, 3}},
+		{"filter by tag", func(rule DataRule) bool { return rule.Tag == "cats" }, []int{1, 3}},
+		{"nothing matches", func(rule DataRule) bool { return false }, []int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := DeleteRequestFromResponse(res, tt.filter)
+
+			if len(result.Delete.Ids) != len(tt.ids) {
+				t.Fatalf("got %v, want %v", result.Delete.Ids, tt.ids)
+			}
+			for i, id := range tt.ids {
+				if result.Delete.Ids[i] != id {
+					t.Errorf("got %v, want %v", result.Delete.Ids, tt.ids)
+				}
+			}
+		})
+	}
+}
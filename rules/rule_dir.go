@@ -0,0 +1,118 @@
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// RuleFileExt is the extension of the files read by LoadRulesFromDir.
+const RuleFileExt = ".rule"
+
+// ErrInvalidRuleFiles is the error wrapped by a RuleFilesError. Use errors.Is to detect it.
+var ErrInvalidRuleFiles = errors.New("invalid rule files")
+
+type (
+	// RuleFileIssue is a problem with a file read by LoadRulesFromDir. File is its name within the directory.
+	RuleFileIssue struct {
+		File    string
+		Message string
+	}
+
+	// RuleFilesError is returned by LoadRulesFromDir with every problem found, so they can all be fixed at once.
+	RuleFilesError struct {
+		Issues []RuleFileIssue
+	}
+)
+
+// Error implements the error interface.
+func (e *RuleFilesError) Error() string {
+	issues := make([]string, len(e.Issues))
+	for i, issue := range e.Issues {
+		issues[i] = fmt.Sprintf("%s: %s", issue.File, issue.Message)
+	}
+	return fmt.Sprintf("%v: %s", ErrInvalidRuleFiles, strings.Join(issues, "; "))
+}
+
+// Unwrap returns ErrInvalidRuleFiles.
+func (e *RuleFilesError) Unwrap() error {
+	return ErrInvalidRuleFiles
+}
+
+// LoadRulesFromDir reads a rule from every `.rule` file of `path`, in file name order, so every rule can be
+// reviewed and changed on its own, e.g. in git. Other files and subdirectories are ignored.
+// A file holds the value of its rule, which may be split over several lines that are trimmed and joined with a space.
+// A first line starting with "# " is a comment holding the tag of the rule, e.g. `# cats`.
+// Since hashtags also start with '#', a first line like `#cats` is part of the value.
+// Empty values, unbalanced quotes or parentheses, dangling operators and tags used by several files are reported
+// together in a RuleFilesError. The request can then be passed to `SetRules`, or exported with its JSON method.
+func LoadRulesFromDir(path string) (CreateRulesRequest, error) {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return CreateRulesRequest{}, err
+	}
+
+	req := CreateRulesRequest{}
+	var issues []RuleFileIssue
+	tags := make(map[string]string)
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != RuleFileExt {
+			continue
+		}
+		name := entry.Name()
+		issue := func(format string, args ...interface{}) {
+			issues = append(issues, RuleFileIssue{File: name, Message: fmt.Sprintf(format, args...)})
+		}
+
+		b, err := os.ReadFile(filepath.Join(path, name))
+		if err != nil {
+			issue("%v", err)
+			continue
+		}
+
+		value, tag := parseRuleFile(string(b))
+		if value == "" {
+			issue("value is empty")
+			continue
+		}
+		for _, problem := range syntaxProblems(value) {
+			issue(problem)
+		}
+		if tag != "" {
+			if first, ok := tags[tag]; ok {
+				issue("tag %q is also used by %s", tag, first)
+			} else {
+				tags[tag] = name
+			}
+		}
+
+		rule := &RuleValue{Value: &value}
+		if tag != "" {
+			rule.Tag = &tag
+		}
+		req.Add = append(req.Add, rule)
+	}
+
+	if len(issues) > 0 {
+		return CreateRulesRequest{}, &RuleFilesError{Issues: issues}
+	}
+	return req, nil
+}
+
+// parseRuleFile returns the value of a rule file, and the tag of its first comment line.
+func parseRuleFile(content string) (value string, tag string) {
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	if first := strings.TrimSpace(lines[0]); first == "#" || strings.HasPrefix(first, "# ") {
+		tag = strings.TrimSpace(strings.TrimPrefix(first, "#"))
+		lines = lines[1:]
+	}
+	var parts []string
+	for _, line := range lines {
+		if line = strings.TrimSpace(line); line != "" {
+			parts = append(parts, line)
+		}
+	}
+	return strings.Join(parts, " "), tag
+}
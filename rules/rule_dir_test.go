@@ -0,0 +1,88 @@
+package rules
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func givenRuleDir(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestLoadRulesFromDir(t *testing.T) {
+	t.Run("reads every rule file in order", func(t *testing.T) {
+		dir := givenRuleDir(t, map[string]string{
+			"b.rule":    "# dogs\ndog\n  OR puppy\n",
+			"a.rule":    "cat has:images\n",
+			"c.rule":    "#golang OR #go",
+			"notes.txt": "not a rule",
+		})
+		if err := os.Mkdir(filepath.Join(dir, "sub.rule"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		req, err := LoadRulesFromDir(dir)
+		if err != nil {
+			t.Fatalf("got err %v", err)
+		}
+
+		if len(req.Add) != 3 {
+			t.Fatalf("got %d rules, want 3", len(req.Add))
+		}
+		if *req.Add[0].Value != "cat has:images" || req.Add[0].Tag != nil {
+			t.Errorf("got %+v, want an untagged rule", req.Add[0])
+		}
+		if *req.Add[1].Value != "dog OR puppy" || *req.Add[1].Tag != "dogs" {
+			t.Errorf("got %q %q, want the joined value and the tag", *req.Add[1].Value, *req.Add[1].Tag)
+		}
+		if *req.Add[2].Value != "#golang OR #go" || req.Add[2].Tag != nil {
+			t.Errorf("got %+v, want a leading hashtag kept in the value", req.Add[2])
+		}
+	})
+
+	t.Run("reports the problems of every file", func(t *testing.T) {
+		dir := givenRuleDir(t, map[string]string{
+			"empty.rule":     "# empty\n\n",
+			"quote.rule":     "# quote\n\"cat\n",
+			"duplicate.rule": "# quote\ndog\n",
+		})
+
+		_, err := LoadRulesFromDir(dir)
+
+		var filesErr *RuleFilesError
+		if !errors.As(err, &filesErr) || !errors.Is(err, ErrInvalidRuleFiles) {
+			t.Fatalf("got err %v, want a RuleFilesError", err)
+		}
+		want := []RuleFileIssue{
+			{File: "empty.rule", Message: "value is empty"},
+			{File: "quote.rule", Message: "quote is not closed"},
+			{File: "quote.rule", Message: `tag "quote" is also used by duplicate.rule`},
+		}
+		if len(filesErr.Issues) != len(want) {
+			t.Fatalf("got %+v, want %+v", filesErr.Issues, want)
+		}
+		for i := range want {
+			if filesErr.Issues[i] != want[i] {
+				t.Errorf("got %+v, want %+v", filesErr.Issues[i], want[i])
+			}
+		}
+		if !strings.Contains(err.Error(), "empty.rule: value is empty") {
+			t.Errorf("got %q, want the file names in the message", err.Error())
+		}
+	})
+
+	t.Run("missing directory", func(t *testing.T) {
+		if _, err := LoadRulesFromDir(filepath.Join(t.TempDir(), "missing")); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("got err %v, want %v", err, os.ErrNotExist)
+		}
+	})
+}
@@ -0,0 +1,160 @@
+package rules
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// DefaultLintMaxRules is the number of rules the elevated access level can track at once.
+	DefaultLintMaxRules = 25
+	// DefaultLintMaxLength is the longest rule value the elevated access level accepts.
+	DefaultLintMaxLength = 512
+)
+
+type (
+	// LintOptions sets the limits LintRulesFile checks against. Zero values use the Default* limits.
+	LintOptions struct {
+		MaxRules  int
+		MaxLength int
+	}
+
+	// RuleLintIssue is a problem found by LintRulesFile.
+	// Index is the position of the rule in the file, or -1 if the issue is with the file as a whole.
+	RuleLintIssue struct {
+		Index   int
+		Value   string
+		Tag     string
+		Message string
+	}
+)
+
+// LintRulesFile validates a rules file without calling the API, so rule changes can be checked in CI.
+// The file is either a CreateRulesRequest (`{"add": [...]}`) or a bare array of rules.
+// It checks every value is non-empty, within the length limit and has balanced quotes and parentheses,
+// that no operator dangles, that tags are unique and that the file is within the rule limit.
+// No issues means the file is valid.
+func LintRulesFile(b []byte, opts LintOptions) []RuleLintIssue {
+	if opts.MaxRules <= 0 {
+		opts.MaxRules = DefaultLintMaxRules
+	}
+	if opts.MaxLength <= 0 {
+		opts.MaxLength = DefaultLintMaxLength
+	}
+
+	rules, err := parseRulesFile(b)
+	if err != nil {
+		return []RuleLintIssue{{Index: -1, Message: fmt.Sprintf("invalid rules file: %v", err)}}
+	}
+
+	var issues []RuleLintIssue
+	if len(rules) > opts.MaxRules {
+		issues = append(issues, RuleLintIssue{
+			Index:   -1,
+			Message: fmt.Sprintf("file has %d rules, more than the limit of %d", len(rules), opts.MaxRules),
+		})
+	}
+
+	tags := make(map[string]int)
+	for i, rule := range rules {
+		value, tag := lintValue(rule)
+		issue := func(format string, args ...interface{}) {
+			issues = append(issues, RuleLintIssue{Index: i, Value: value, Tag: tag, Message: fmt.Sprintf(format, args...)})
+		}
+
+		if isEmptyRule(rule) {
+			issue("value is empty")
+			continue
+		}
+		if length := utf8.RuneCountInString(value); length > opts.MaxLength {
+			issue("value is %d characters, more than the limit of %d", length, opts.MaxLength)
+		}
+		for _, problem := range syntaxProblems(value) {
+			issue(problem)
+		}
+
+		if tag == "" {
+			continue
+		}
+		if first, ok := tags[tag]; ok {
+			issue("tag is also used by rule %d", first)
+		} else {
+			tags[tag] = i
+		}
+	}
+
+	return issues
+}
+
+// parseRulesFile reads either a CreateRulesRequest or a bare array of rules.
+func parseRulesFile(b []byte) ([]*RuleValue, error) {
+	if trimmed := bytes.TrimSpace(b); len(trimmed) > 0 && trimmed[0] == '[' {
+		var rules []*RuleValue
+		err := json.Unmarshal(trimmed, &rules)
+		return rules, err
+	}
+
+	var req CreateRulesRequest
+	err := json.Unmarshal(b, &req)
+	return req.Add, err
+}
+
+func lintValue(rule *RuleValue) (value string, tag string) {
+	if rule == nil {
+		return "", ""
+	}
+	if rule.Value != nil {
+		value = *rule.Value
+	}
+	if rule.Tag != nil {
+		tag = *rule.Tag
+	}
+	return value, tag
+}
+
+// syntaxProblems does a basic check of a rule value's operators. It does not know every operator,
+// it only catches the mistakes that always fail: unbalanced quotes or parentheses and dangling operators.
+func syntaxProblems(value string) []string {
+	var problems []string
+
+	depth, quoted := 0, false
+	for _, r := range value {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case quoted:
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+			if depth < 0 {
+				problems = append(problems, "closing parenthesis has no opening parenthesis")
+				depth = 0
+			}
+		}
+	}
+	if quoted {
+		problems = append(problems, "quote is not closed")
+	}
+	if depth > 0 {
+		problems = append(problems, "parenthesis is not closed")
+	}
+
+	words := strings.Fields(strings.NewReplacer("(", " ( ", ")", " ) ").Replace(value))
+	for i, word := range words {
+		first, last := i == 0 || words[i-1] == "(", i == len(words)-1 || words[i+1] == ")"
+		switch {
+		case word == "OR" && (first || last || words[i+1] == "OR"):
+			problems = append(problems, "OR is missing an operand")
+		case word == "-" || (strings.HasSuffix(word, ":") && last):
+			problems = append(problems, fmt.Sprintf("%q is missing an operand", word))
+		case word == "(" && i+1 < len(words) && words[i+1] == ")":
+			problems = append(problems, "parentheses are empty")
+		}
+	}
+
+	return problems
+}
@@ -0,0 +1,64 @@
+package rules
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestLintRulesFile(t *testing.T) {
+	var tests = []struct {
+		file   string
+		opts   LintOptions
+		issues []RuleLintIssue
+	}{
+		{`{"add":[{"value":"cats has:images","tag":"cats"},{"value":"(dogs OR puppies) -is:retweet","tag":"dogs"}]}`, LintOptions{}, nil},
+		{`[{"value":"\"hot dog\" lang:en"}]`, LintOptions{}, nil},
+		{`{"add":[`, LintOptions{}, []RuleLintIssue{{Index: -1}}},
+		{`[{"value":"  ","tag":"blank"}]`, LintOptions{}, []RuleLintIssue{{Index: 0, Value: "  ", Tag: "blank", Message: "value is empty"}}},
+		{`[{"value":"cats"},{"value":"dogs"}]`, LintOptions{MaxRules: 1}, []RuleLintIssue{{Index: -1}}},
+		{`[{"value":"cats dogs"}]`, LintOptions{MaxLength: 4}, []RuleLintIssue{{Index: 0, Value: "cats dogs"}}},
+		{`[{"value":"猫 犬"}]`, LintOptions{MaxLength: 3}, nil},
+		{`[{"value":"猫 犬 鳥"}]`, LintOptions{MaxLength: 3}, []RuleLintIssue{
+			{Index: 0, Value: "猫 犬 鳥", Message: "value is 5 characters, more than the limit of 3"},
+		}},
+		{`[{"value":"(cats"},{"value":"cats)"},{"value":"\"cats"}]`, LintOptions{}, []RuleLintIssue{
+			{Index: 0, Value: "(cats", Message: "parenthesis is not closed"},
+			{Index: 1, Value: "cats)", Message: "closing parenthesis has no opening parenthesis"},
+			{Index: 2, Value: "\"cats", Message: "quote is not closed"},
+		}},
+		{`[{"value":"cats OR"},{"value":"(OR dogs)"},{"value":"cats - dogs"},{"value":"cats lang:"},{"value":"cats ()"}]`, LintOptions{}, []RuleLintIssue{
+			{Index: 0, Value: "cats OR", Message: "OR is missing an operand"},
+			{Index: 1, Value: "(OR dogs)", Message: "OR is missing an operand"},
+			{Index: 2, Value: "cats - dogs", Message: "\"-\" is missing an operand"},
+			{Index: 3, Value: "cats lang:", Message: "\"lang:\" is missing an operand"},
+			{Index: 4, Value: "cats ()", Message: "parentheses are empty"},
+		}},
+		{`[{"value":"cats","tag":"pets"},{"value":"dogs","tag":"pets"}]`, LintOptions{}, []RuleLintIssue{
+			{Index: 1, Value: "dogs", Tag: "pets", Message: "tag is also used by rule 0"},
+		}},
+	}
+
+	for i, tt := range tests {
+		testName := fmt.Sprintf("TestLintRulesFile (%d)", i)
+
+		t.Run(testName, func(t *testing.T) {
+			issues := LintRulesFile([]byte(tt.file), tt.opts)
+
+			if len(issues) != len(tt.issues) {
+				t.Fatalf("got %d issues %v, want %d", len(issues), issues, len(tt.issues))
+			}
+			for j, want := range tt.issues {
+				got := issues[j]
+				if got.Index != want.Index || got.Value != want.Value || got.Tag != want.Tag {
+					t.Errorf("got issue %+v, want %+v", got, want)
+				}
+				if want.Message != "" && got.Message != want.Message {
+					t.Errorf("got message %q, want %q", got.Message, want.Message)
+				}
+				if got.Message == "" {
+					t.Errorf("got issue %+v without a message", got)
+				}
+			}
+		})
+	}
+}
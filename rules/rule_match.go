@@ -0,0 +1,281 @@
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"dev.freespoke.com/twitter-stream/tweet"
+)
+
+// ErrUnsupportedOperator is returned by RuleMatches for an operator outside of the subset it can evaluate.
+var ErrUnsupportedOperator = errors.New("rule operator is not supported by the local matcher")
+
+type (
+	// ruleNode is a parsed rule, or part of one, that can be evaluated against a tweet.
+	ruleNode interface {
+		matches(t *tweetTerms) bool
+	}
+
+	andNode []ruleNode
+	orNode  []ruleNode
+	notNode struct{ node ruleNode }
+	// termNode is a keyword, a "quoted phrase", or an operator.
+	termNode struct{ match func(t *tweetTerms) bool }
+
+	// ruleParser is a recursive descent parser over the tokens of a rule value.
+	ruleParser struct {
+		tokens []string
+		pos    int
+	}
+
+	// tweetTerms is a tweet with its text split into lowercase words, as rules are matched against it.
+	tweetTerms struct {
+		tweet *tweet.Tweet
+		words []string
+	}
+)
+
+// RuleMatches is a best-effort local matcher, for unit testing rules without calling Twitter.
+// It evaluates a subset of the rule grammar against a decoded tweet:
+//
+//   - keywords, #hashtags, @mentions and "quoted phrases", matched as whole words ignoring case
+//   - from:<user id>, matched against the AuthorID, since the tweet itself has no username
+//   - has:media, has:links, has:hashtags and has:mentions, which need the attachments or entities tweet fields
+//   - is:retweet, is:reply and is:quote, which need the referenced_tweets tweet field
+//   - lang:<code>, which needs the lang tweet field
+//   - grouping with parentheses, OR, and negation with a leading -
+//
+// Any other operator returns ErrUnsupportedOperator. Twitter's own tokenization is more involved, so a rule
+// that matches locally is not guaranteed to match on the stream.
+func RuleMatches(ruleValue string, t *tweet.Tweet) (bool, error) {
+	tokens, err := tokenizeRule(ruleValue)
+	if err != nil {
+		return false, err
+	}
+	if len(tokens) == 0 {
+		return false, errors.New("rule value is empty")
+	}
+
+	parser := &ruleParser{tokens: tokens}
+	node, err := parser.parseOr()
+	if err != nil {
+		return false, err
+	}
+	if parser.pos < len(tokens) {
+		return false, fmt.Errorf("unexpected %q in rule value", tokens[parser.pos])
+	}
+
+	return node.matches(&tweetTerms{tweet: t, words: splitWords(t.Text)}), nil
+}
+
+// tokenizeRule splits a rule value into parentheses, a leading -, quoted phrases, and words.
+func tokenizeRule(value string) ([]string, error) {
+	var tokens []string
+	runes := []rune(value)
+	for i := 0; i < len(runes); i++ {
+		switch r := runes[i]; {
+		case unicode.IsSpace(r):
+		case r == '(' || r == ')' || r == '-':
+			tokens = append(tokens, string(r))
+		case r == '"':
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			if end == len(runes) {
+				return nil, errors.New("quote is not closed in rule value")
+			}
+			tokens = append(tokens, string(runes[i:end+1]))
+			i = end
+		default:
+			end := i
+			for end < len(runes) && !unicode.IsSpace(runes[end]) && runes[end] != '(' && runes[end] != ')' && runes[end] != '"' {
+				end++
+			}
+			tokens = append(tokens, string(runes[i:end]))
+			i = end - 1
+		}
+	}
+	return tokens, nil
+}
+
+func (p *ruleParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *ruleParser) parseOr() (ruleNode, error) {
+	var nodes orNode
+	for {
+		node, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+		if p.peek() != "OR" {
+			break
+		}
+		p.pos++
+	}
+	if len(nodes) == 1 {
+		return nodes[0], nil
+	}
+	return nodes, nil
+}
+
+func (p *ruleParser) parseAnd() (ruleNode, error) {
+	var nodes andNode
+	for next := p.peek(); next != "" && next != "OR" && next != ")"; next = p.peek() {
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+	if len(nodes) == 0 {
+		return nil, errors.New("operator is missing an operand in rule value")
+	}
+	if len(nodes) == 1 {
+		return nodes[0], nil
+	}
+	return nodes, nil
+}
+
+func (p *ruleParser) parseUnary() (ruleNode, error) {
+	if p.peek() == "-" {
+		p.pos++
+		if next := p.peek(); next == "" || next == "OR" || next == ")" {
+			return nil, errors.New("- is missing an operand in rule value")
+		}
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{node}, nil
+	}
+
+	token := p.peek()
+	p.pos++
+	if token != "(" {
+		return parseTerm(token)
+	}
+
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek() != ")" {
+		return nil, errors.New("parenthesis is not closed in rule value")
+	}
+	p.pos++
+	return node, nil
+}
+
+// parseTerm turns a keyword, phrase, or operator token into a term.
+func parseTerm(token string) (ruleNode, error) {
+	if strings.HasPrefix(token, `"`) {
+		phrase := splitWords(strings.Trim(token, `"`))
+		return termNode{func(t *tweetTerms) bool { return t.hasPhrase(phrase) }}, nil
+	}
+
+	operator, arg, isOperator := strings.Cut(token, ":")
+	if !isOperator || strings.Contains(operator, "/") {
+		words := splitWords(token)
+		return termNode{func(t *tweetTerms) bool { return t.hasPhrase(words) }}, nil
+	}
+
+	switch token {
+	case "has:media":
+		return termNode{func(t *tweetTerms) bool {
+			return t.tweet.Attachments != nil && len(t.tweet.Attachments.MediaKeys) > 0
+		}}, nil
+	case "has:links":
+		return termNode{func(t *tweetTerms) bool { return t.tweet.Entities != nil && len(t.tweet.Entities.URLs) > 0 }}, nil
+	case "has:hashtags":
+		return termNode{func(t *tweetTerms) bool { return t.tweet.Entities != nil && len(t.tweet.Entities.Hashtags) > 0 }}, nil
+	case "has:mentions":
+		return termNode{func(t *tweetTerms) bool { return t.tweet.Entities != nil && len(t.tweet.Entities.Mentions) > 0 }}, nil
+	case "is:retweet":
+		return termNode{func(t *tweetTerms) bool { return t.references("retweeted") }}, nil
+	case "is:reply":
+		return termNode{func(t *tweetTerms) bool { return t.references("replied_to") }}, nil
+	case "is:quote":
+		return termNode{func(t *tweetTerms) bool { return t.references("quoted") }}, nil
+	}
+
+	switch {
+	case operator == "from" && arg != "":
+		return termNode{func(t *tweetTerms) bool { return t.tweet.AuthorID == arg }}, nil
+	case operator == "lang" && arg != "":
+		return termNode{func(t *tweetTerms) bool { return strings.EqualFold(t.tweet.Lang, arg) }}, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrUnsupportedOperator, token)
+}
+
+func (n andNode) matches(t *tweetTerms) bool {
+	for _, node := range n {
+		if !node.matches(t) {
+			return false
+		}
+	}
+	return true
+}
+
+func (n orNode) matches(t *tweetTerms) bool {
+	for _, node := range n {
+		if node.matches(t) {
+			return true
+		}
+	}
+	return false
+}
+
+func (n notNode) matches(t *tweetTerms) bool {
+	return !n.node.matches(t)
+}
+
+func (n termNode) matches(t *tweetTerms) bool {
+	return n.match(t)
+}
+
+// hasPhrase returns true if the words appear next to each other in the tweet text.
+func (t *tweetTerms) hasPhrase(phrase []string) bool {
+	if len(phrase) == 0 {
+		return false
+	}
+	for i := 0; i+len(phrase) <= len(t.words); i++ {
+		matched := true
+		for j, word := range phrase {
+			if t.words[i+j] != word {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// references returns true if the tweet references another tweet with the given type.
+func (t *tweetTerms) references(referenceType string) bool {
+	for _, reference := range t.tweet.ReferencedTweets {
+		if reference.Type == referenceType {
+			return true
+		}
+	}
+	return false
+}
+
+// splitWords splits text into lowercase words, keeping the # and @ of hashtags and mentions.
+func splitWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '#' && r != '@' && r != '$'
+	})
+}
@@ -0,0 +1,74 @@
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"dev.freespoke.com/twitter-stream/tweet"
+)
+
+func TestRuleMatches(t *testing.T) {
+	catTweet := &tweet.Tweet{
+		Text:        "My cat loves #caturday, says @twitterdev",
+		AuthorID:    "2244994945",
+		Lang:        "en",
+		Attachments: &tweet.Attachments{MediaKeys: []string{"3_1"}},
+	}
+	retweet := &tweet.Tweet{
+		Text:             "RT Hot dogs are sandwiches",
+		Lang:             "es",
+		ReferencedTweets: []tweet.ReferencedTweet{{Type: "retweeted", ID: "1"}},
+	}
+
+	var tests = []struct {
+		rule    string
+		tweet   *tweet.Tweet
+		matches bool
+		err     bool
+	}{
+		{"cat", catTweet, true, false},
+		{"CAT loves", catTweet, true, false},
+		{"cat dog", catTweet, false, false},
+		{"cat OR dog", catTweet, true, false},
+		{"#caturday @twitterdev", catTweet, true, false},
+		{"caturday", catTweet, false, false},
+		{`"cat loves"`, catTweet, true, false},
+		{`"loves cat"`, catTweet, false, false},
+		{"from:2244994945 has:media lang:en", catTweet, true, false},
+		{"cat -has:media", catTweet, false, false},
+		{"(cats OR puppies) is:retweet", retweet, false, false},
+		{"(dogs OR cats) is:retweet", &tweet.Tweet{Text: "dogs", ReferencedTweets: retweet.ReferencedTweets}, true, false},
+		{"\"hot dogs\" -is:retweet", retweet, false, false},
+		{"sandwiches -(lang:en OR lang:fr)", retweet, true, false},
+		{"cat place_country:US", catTweet, false, true},
+		{"(cat", catTweet, false, true},
+		{"cat)", catTweet, false, true},
+		{"cat OR", catTweet, false, true},
+		{"\"cat", catTweet, false, true},
+		{"", catTweet, false, true},
+	}
+
+	for i, tt := range tests {
+		testName := fmt.Sprintf("TestRuleMatches (%d)", i)
+
+		t.Run(testName, func(t *testing.T) {
+			matches, err := RuleMatches(tt.rule, tt.tweet)
+
+			if (err != nil) != tt.err {
+				t.Fatalf("got err %v, want err %v", err, tt.err)
+			}
+			if matches != tt.matches {
+				t.Errorf("got %v for %q, want %v", matches, tt.rule, tt.matches)
+			}
+		})
+	}
+}
+
+func TestRuleMatchesRejectsUnsupportedOperators(t *testing.T) {
+	_, err := RuleMatches("cats point_radius:[2.35 48.85 10km]", &tweet.Tweet{})
+
+	if !errors.Is(err, ErrUnsupportedOperator) {
+		t.Errorf("got %v, want %v", err, ErrUnsupportedOperator)
+	}
+}
@@ -0,0 +1,79 @@
+package rules
+
+// NotCreatedRule describes a rule that Twitter did not create and why.
+type NotCreatedRule struct {
+	Value string
+	Title string
+	Type  string
+}
+
+// NotCreatedReport turns the Errors array into a report of every rule that was not created and why.
+// Twitter may report more rules in MetaSummary.NotCreated than it returned errors for; use
+// UnexplainedNotCreated to get how many.
+func (r *TwitterRuleResponse) NotCreatedReport() []NotCreatedRule {
+	report := make([]NotCreatedRule, 0, len(r.Errors))
+	for _, e := range r.Errors {
+		report = append(report, NotCreatedRule{Value: e.Value, Title: e.Title, Type: e.Type})
+	}
+
+	return report
+}
+
+// UnexplainedNotCreated returns how many rules MetaSummary.NotCreated counts beyond the errors Twitter returned.
+func (r *TwitterRuleResponse) UnexplainedNotCreated() uint {
+	if explained := uint(len(r.Errors)); r.Meta.Summary.NotCreated > explained {
+		return r.Meta.Summary.NotCreated - explained
+	}
+	return 0
+}
+
+// IsEmpty reports whether the response has no rules, e.g. when `Get` is called before any rule was created.
+func (r *TwitterRuleResponse) IsEmpty() bool {
+	return len(r.Data) == 0
+}
+
+// RuleIDs returns the ids of the rules in Data, in order.
+func (r *TwitterRuleResponse) RuleIDs() []string {
+	ids := make([]string, 0, len(r.Data))
+	for _, rule := range r.Data {
+		ids = append(ids, rule.Id)
+	}
+	return ids
+}
+
+// RuleValues returns the values of the rules in Data, in order.
+func (r *TwitterRuleResponse) RuleValues() []string {
+	values := make([]string, 0, len(r.Data))
+	for _, rule := range r.Data {
+		values = append(values, rule.Value)
+	}
+	return values
+}
+
+// RulesEqual reports whether two responses hold the same rules. Rules are compared by value and tag only:
+// server ids and the order of Data are ignored, and the Meta and Errors of the responses are not compared.
+// A rule that appears twice must appear twice in both. A nil response equals a response without rules.
+func RulesEqual(a, b *TwitterRuleResponse) bool {
+	counts := make(map[ruleKey]int)
+	if a != nil {
+		for _, rule := range a.Data {
+			counts[ruleKey{value: rule.Value, tag: rule.Tag}]++
+		}
+	}
+	if b != nil {
+		for _, rule := range b.Data {
+			key := ruleKey{value: rule.Value, tag: rule.Tag}
+			if counts[key] == 0 {
+				return false
+			}
+			counts[key]--
+		}
+	}
+
+	for _, count := range counts {
+		if count != 0 {
+			return false
+		}
+	}
+	return true
+}
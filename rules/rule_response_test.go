@@ -0,0 +1,129 @@
+package rules
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func givenTwitterRuleResponse(t *testing.T, payload string) *TwitterRuleResponse {
+	res := new(TwitterRuleResponse)
+	if err := json.Unmarshal([]byte(payload), res); err != nil {
+		t.Fatal(err)
+	}
+	return res
+}
+
+func TestNotCreatedReportWithMixedResponse(t *testing.T) {
+	res := givenTwitterRuleResponse(t, `{
+		"data": [{"value": "cat has:images", "tag": "cats", "id": "1"}],
+		"meta": {"sent": "today", "summary": {"created": 1, "not_created": 2}},
+		"errors": [
+			{"value": "dog has:nonsense", "title": "UnprocessableEntity", "type": "https://api.twitter.com/2/problems/invalid-rules"},
+			{"value": "cat", "id": "2", "title": "DuplicateRule", "type": "https://api.twitter.com/2/problems/duplicate-rules"}
+		]
+	}`)
+
+	report := res.NotCreatedReport()
+
+	if len(report) != 2 {
+		t.Fatalf("got %d entries, want 2", len(report))
+	}
+
+	if report[0].Value != "dog has:nonsense" || report[0].Title != "UnprocessableEntity" {
+		t.Errorf("got %+v, want dog has:nonsense UnprocessableEntity", report[0])
+	}
+
+	if report[1].Value != "cat" || report[1].Type != "https://api.twitter.com/2/problems/duplicate-rules" {
+		t.Errorf("got %+v, want cat duplicate-rules", report[1])
+	}
+}
+
+func TestNotCreatedReportOnlyHasTwitterErrors(t *testing.T) {
+	res := givenTwitterRuleResponse(t, `{
+		"meta": {"sent": "today", "summary": {"created": 0, "not_created": 3}},
+		"errors": [{"value": "cat", "id": "2", "title": "DuplicateRule"}]
+	}`)
+
+	if report := res.NotCreatedReport(); len(report) != 1 {
+		t.Errorf("got %d entries, want 1", len(report))
+	}
+
+	if res.UnexplainedNotCreated() != 2 {
+		t.Errorf("got %d unexplained, want 2", res.UnexplainedNotCreated())
+	}
+}
+
+func TestNotCreatedReportIsEmptyWhenEverythingWasCreated(t *testing.T) {
+	res := givenTwitterRuleResponse(t, `{"meta": {"sent": "today", "summary": {"created": 2, "not_created": 0}}}`)
+
+	if len(res.NotCreatedReport()) != 0 {
+		t.Errorf("got %v, want empty report", res.NotCreatedReport())
+	}
+
+	if res.UnexplainedNotCreated() != 0 {
+		t.Errorf("got %d unexplained, want 0", res.UnexplainedNotCreated())
+	}
+}
+
+func TestRuleAccessorsWithRules(t *testing.T) {
+	res := givenTwitterRuleResponse(t, `{
+		"data": [{"value": "cat has:images", "tag": "cats", "id": "1"}, {"value": "dog", "id": "2"}],
+		"meta": {"sent": "today"}
+	}`)
+
+	if res.IsEmpty() {
+		t.Errorf("expected the response not to be empty")
+	}
+
+	if ids := res.RuleIDs(); len(ids) != 2 || ids[0] != "1" || ids[1] != "2" {
+		t.Errorf("got %v, want [1 2]", ids)
+	}
+
+	if values := res.RuleValues(); len(values) != 2 || values[0] != "cat has:images" || values[1] != "dog" {
+		t.Errorf("got %v, want [cat has:images dog]", values)
+	}
+}
+
+func TestRuleAccessorsWithoutRules(t *testing.T) {
+	res := givenTwitterRuleResponse(t, `{"meta": {"sent": "today"}}`)
+
+	if !res.IsEmpty() {
+		t.Errorf("expected the response to be empty")
+	}
+
+	if len(res.RuleIDs()) != 0 || len(res.RuleValues()) != 0 {
+		t.Errorf("got %v and %v, want no ids or values", res.RuleIDs(), res.RuleValues())
+	}
+}
+
+func TestRulesEqual(t *testing.T) {
+	cats := DataRule{Value: "cats", Tag: "pets", Id: "1"}
+	dogs := DataRule{Value: "dogs", Tag: "pets", Id: "2"}
+
+	var tests = []struct {
+		a, b  *TwitterRuleResponse
+		equal bool
+	}{
+		{&TwitterRuleResponse{Data: []DataRule{cats, dogs}}, &TwitterRuleResponse{Data: []DataRule{dogs, cats}}, true},
+		{&TwitterRuleResponse{Data: []DataRule{cats}}, &TwitterRuleResponse{Data: []DataRule{{Value: "cats", Tag: "pets", Id: "9"}}}, true},
+		{&TwitterRuleResponse{Data: []DataRule{cats}}, &TwitterRuleResponse{Data: []DataRule{{Value: "cats", Tag: "felines"}}}, false},
+		{&TwitterRuleResponse{Data: []DataRule{cats, cats}}, &TwitterRuleResponse{Data: []DataRule{cats, dogs}}, false},
+		{&TwitterRuleResponse{Data: []DataRule{cats}}, &TwitterRuleResponse{Data: []DataRule{cats, dogs}}, false},
+		{nil, &TwitterRuleResponse{}, true},
+		{nil, &TwitterRuleResponse{Data: []DataRule{cats}}, false},
+	}
+
+	for i, tt := range tests {
+		testName := fmt.Sprintf("TestRulesEqual (%d)", i)
+
+		t.Run(testName, func(t *testing.T) {
+			if got := RulesEqual(tt.a, tt.b); got != tt.equal {
+				t.Errorf("got %v, want %v", got, tt.equal)
+			}
+			if got := RulesEqual(tt.b, tt.a); got != tt.equal {
+				t.Errorf("got %v swapped, want %v", got, tt.equal)
+			}
+		})
+	}
+}
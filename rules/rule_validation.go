@@ -0,0 +1,50 @@
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Validate checks that the request has at least one rule and that every rule has a non-empty value.
+// It is called by `Create` before the request is sent.
+func (r CreateRulesRequest) Validate() error {
+	if len(r.Add) == 0 {
+		return errors.New("create rules request has no rules")
+	}
+
+	for i, rule := range r.Add {
+		if isEmptyRule(rule) {
+			return fmt.Errorf("create rules request has an empty value for rule %d", i)
+		}
+	}
+
+	return nil
+}
+
+// Validate checks that the request deletes rules either by ids or by values, but not both,
+// and that no value is empty. It is called by `Delete` before the request is sent.
+func (r DeleteRulesRequest) Validate() error {
+	hasIds, hasValues := len(r.Delete.Ids) > 0, len(r.Delete.Values) > 0
+
+	if !hasIds && !hasValues {
+		return errors.New("delete rules request has no ids or values")
+	}
+
+	if hasIds && hasValues {
+		return errors.New("delete rules request can not have both ids and values")
+	}
+
+	for i, value := range r.Delete.Values {
+		if strings.TrimSpace(value) == "" {
+			return fmt.Errorf("delete rules request has an empty value at index %d", i)
+		}
+	}
+
+	return nil
+}
+
+// isEmptyRule reports whether a rule has no value to track.
+func isEmptyRule(rule *RuleValue) bool {
+	return rule == nil || rule.Value == nil || strings.TrimSpace(*rule.Value) == ""
+}
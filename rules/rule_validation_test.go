@@ -0,0 +1,58 @@
+package rules
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCreateRulesRequestValidate(t *testing.T) {
+	var tests = []struct {
+		req       CreateRulesRequest
+		expectErr bool
+	}{
+		{NewRuleBuilder().AddRule("cats", "cat tweets").Build(), false},
+		{NewRuleBuilder().Build(), true},
+		{NewRuleBuilder().AddRule("cats", "").AddRule("  ", "blank").Build(), true},
+		{CreateRulesRequest{Add: []*RuleValue{{Tag: nil, Value: nil}}}, true},
+	}
+
+	for i, tt := range tests {
+		testName := fmt.Sprintf("TestCreateRulesRequestValidate (%d)", i)
+
+		t.Run(testName, func(t *testing.T) {
+			err := tt.req.Validate()
+
+			if (err != nil) != tt.expectErr {
+				t.Errorf("got err %v, want err %v", err, tt.expectErr)
+			}
+		})
+	}
+}
+
+func TestDeleteRulesRequestValidate(t *testing.T) {
+	both := NewDeleteRulesRequest(1)
+	both.Delete.Values = []string{"cats"}
+
+	var tests = []struct {
+		req       DeleteRulesRequest
+		expectErr bool
+	}{
+		{NewDeleteRulesRequest(1, 2), false},
+		{NewDeleteRulesRequestByValue("cats"), false},
+		{NewDeleteRulesRequest(), true},
+		{NewDeleteRulesRequestByValue("cats", ""), true},
+		{both, true},
+	}
+
+	for i, tt := range tests {
+		testName := fmt.Sprintf("TestDeleteRulesRequestValidate (%d)", i)
+
+		t.Run(testName, func(t *testing.T) {
+			err := tt.req.Validate()
+
+			if (err != nil) != tt.expectErr {
+				t.Errorf("got err %v, want err %v", err, tt.expectErr)
+			}
+		})
+	}
+}
@@ -2,7 +2,10 @@ package rules
 
 import (
 	"encoding/json"
+	"log"
 	"net/url"
+	"strconv"
+	"time"
 
 	"dev.freespoke.com/twitter-stream/httpclient"
 )
@@ -12,7 +15,13 @@ type (
 	IRules interface {
 		Create(rules CreateRulesRequest, dryRun bool) (*TwitterRuleResponse, error)
 		Delete(req DeleteRulesRequest, dryRun bool) (*TwitterRuleResponse, error)
+		DeleteAllRules(dryRun bool) (*TwitterRuleResponse, error)
 		Get() (*TwitterRuleResponse, error)
+		GetRulesGrouped() (map[string][]DataRule, error)
+		TestRule(value string) (bool, string, error)
+		SetRules(desired CreateRulesRequest, dryRun bool) (*TwitterRuleResponse, error)
+		SetRulesWithRollback(desired CreateRulesRequest, dryRun bool) (*TwitterRuleResponse, error)
+		RetagRule(id string, newTag string, dryRun bool) (*TwitterRuleResponse, error)
 	}
 
 	//AddRulesRequest
@@ -41,35 +50,90 @@ type (
 	MetaSummary struct {
 		Created    uint `json:"created"`
 		NotCreated uint `json:"not_created"`
+		Deleted    uint `json:"deleted"`
+		NotDeleted uint `json:"not_deleted"`
 	}
 
 	//ErrorRule is what is returned as "Errors" when adding or deleting a rule.
 	ErrorRule struct {
-		Value string `json:"Value"`
-		Id    string `json:"id"`
-		Title string `json:"title"`
-		Type  string `json:"type"`
+		Value   string   `json:"Value"`
+		Id      string   `json:"id"`
+		Title   string   `json:"title"`
+		Type    string   `json:"type"`
+		Details []string `json:"details"`
 	}
 
+	// Option configures a rules instance created with NewRules.
+	Option func(*rules)
+
 	rules struct {
-		httpClient httpclient.IHttpClient
+		httpClient    httpclient.IHttpClient
+		chunkSize     int
+		breaker       *circuitBreaker
+		skewThreshold time.Duration
+		now           func() time.Time
+		logf          func(format string, args ...interface{})
+		namespace     string
+		autoTag       bool
 	}
 )
 
+// DefaultChunkSize is the maximum number of rules sent in a single request, unless WithChunkSize is used.
+const DefaultChunkSize = 100
+
 // NewRules creates a "rules" instance. This is used to create Twitter Filtered Stream rules.
 // https://developer.twitter.com/en/docs/twitter-api/tweets/filtered-stream/integrate/build-a-rule.
-func NewRules(httpClient httpclient.IHttpClient) IRules {
-	return &rules{httpClient: httpClient}
+func NewRules(httpClient httpclient.IHttpClient, opts ...Option) IRules {
+	r := &rules{httpClient: httpClient, chunkSize: DefaultChunkSize, now: time.Now, logf: log.Printf}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// WithChunkSize sets the maximum number of rules sent in a single request. Larger requests, such as
+// creating or deleting hundreds of rules, are split into chunks sent one after the other. Defaults to DefaultChunkSize.
+func WithChunkSize(n int) Option {
+	return func(t *rules) {
+		if n > 0 {
+			t.chunkSize = n
+		}
+	}
 }
 
 // Create will create new twitter streaming rules.
+// Requests with more rules than the chunk size are split into chunks, sent one after the other,
+// and the responses are aggregated. If a chunk fails, the responses of the chunks before it are returned with the error.
 func (t *rules) Create(rules CreateRulesRequest, dryRun bool) (*TwitterRuleResponse, error) {
-	body, err := json.Marshal(rules)
+	if err := rules.Validate(); err != nil {
+		return nil, err
+	}
+	rules = t.namespaced(t.autoTagged(rules))
+
+	if len(rules.Add) <= t.chunkSize {
+		return t.create(rules, dryRun)
+	}
+
+	aggregated := new(TwitterRuleResponse)
+	for start := 0; start < len(rules.Add); start += t.chunkSize {
+		res, err := t.create(CreateRulesRequest{Add: chunkOf(rules.Add, start, t.chunkSize)}, dryRun)
+		if err != nil {
+			return aggregated, err
+		}
+		aggregated.merge(res)
+	}
+
+	return aggregated, nil
+}
+
+// create sends a single create request.
+func (t *rules) create(rules CreateRulesRequest, dryRun bool) (*TwitterRuleResponse, error) {
+	body, err := rules.JSON()
 	if err != nil {
 		return nil, err
 	}
 
-	res, err := t.httpClient.AddRules(t.addDryRun(dryRun), string(body))
+	res, err := t.addRules(t.addDryRun(dryRun), string(body))
 
 	if err != nil {
 		return nil, err
@@ -79,19 +143,69 @@ func (t *rules) Create(rules CreateRulesRequest, dryRun bool) (*TwitterRuleRespo
 	data := new(TwitterRuleResponse)
 
 	err = json.NewDecoder(res.Body).Decode(data)
+	t.checkClockSkew(data)
+	t.unscope(data)
 	return data, err
 }
 
-// Delete will delete rules twitter rules by their id.
+// Delete will delete rules twitter rules by their id, or by their value.
+// Requests with more ids or values than the chunk size are split into chunks, sent one after the other,
+// and the responses are aggregated. If a chunk fails, the responses of the chunks before it are returned with the error.
 func (t *rules) Delete(req DeleteRulesRequest, dryRun bool) (*TwitterRuleResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if err := t.checkNamespace(req); err != nil {
+		return nil, err
+	}
+
+	return t.deleteChunked(req, dryRun)
+}
+
+// deleteChunked sends a delete request in chunks of at most the chunk size.
+func (t *rules) deleteChunked(req DeleteRulesRequest, dryRun bool) (*TwitterRuleResponse, error) {
+	ids, values := req.Delete.Ids, req.Delete.Values
+	if len(ids) <= t.chunkSize && len(values) <= t.chunkSize {
+		return t.delete(req, dryRun)
+	}
+
+	aggregated := new(TwitterRuleResponse)
+	for start := 0; start < len(ids) || start < len(values); start += t.chunkSize {
+		chunk := DeleteRulesRequest{}
+		chunk.Delete.Ids = chunkOf(ids, start, t.chunkSize)
+		chunk.Delete.Values = chunkOf(values, start, t.chunkSize)
+
+		res, err := t.delete(chunk, dryRun)
+		if err != nil {
+			return aggregated, err
+		}
+		aggregated.merge(res)
+	}
+
+	return aggregated, nil
+}
 
+// chunkOf returns the chunk of `list` starting at `start`, or nil past its end.
+func chunkOf[T any](list []T, start int, size int) []T {
+	if start >= len(list) {
+		return nil
+	}
+	end := start + size
+	if end > len(list) {
+		end = len(list)
+	}
+	return list[start:end]
+}
+
+// delete sends a single delete request.
+func (t *rules) delete(req DeleteRulesRequest, dryRun bool) (*TwitterRuleResponse, error) {
 	body, err := json.Marshal(req)
 
 	if err != nil {
 		return nil, err
 	}
 
-	res, err := t.httpClient.AddRules(t.addDryRun(dryRun), string(body))
+	res, err := t.addRules(t.addDryRun(dryRun), string(body))
 
 	if err != nil {
 		return nil, err
@@ -101,12 +215,51 @@ func (t *rules) Delete(req DeleteRulesRequest, dryRun bool) (*TwitterRuleRespons
 	data := new(TwitterRuleResponse)
 
 	err = json.NewDecoder(res.Body).Decode(data)
+	t.checkClockSkew(data)
 	return data, err
 }
 
+// DeleteAllRules will fetch the current rules and delete all of them.
+// Rules are deleted in chunks, like with `Delete`, and the responses are aggregated.
+func (t *rules) DeleteAllRules(dryRun bool) (*TwitterRuleResponse, error) {
+	current, err := t.Get()
+
+	if err != nil {
+		return nil, err
+	}
+
+	ids, err := ruleIds(current.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.deleteIds(ids, dryRun)
+}
+
+// deleteIds deletes rules by their ids, doing nothing if there are none.
+func (t *rules) deleteIds(ids []int, dryRun bool) (*TwitterRuleResponse, error) {
+	if len(ids) == 0 {
+		return new(TwitterRuleResponse), nil
+	}
+	return t.deleteChunked(NewDeleteRulesRequest(ids...), dryRun)
+}
+
+// ruleIds parses the ids of rules returned by twitter.
+func ruleIds(rules []DataRule) ([]int, error) {
+	ids := make([]int, 0, len(rules))
+	for _, rule := range rules {
+		id, err := strconv.ParseInt(rule.Id, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, int(id))
+	}
+	return ids, nil
+}
+
 // Get will fetch the current rules.
 func (t *rules) Get() (*TwitterRuleResponse, error) {
-	res, err := t.httpClient.GetRules()
+	res, err := t.getRules()
 
 	if err != nil {
 		return nil, err
@@ -115,10 +268,59 @@ func (t *rules) Get() (*TwitterRuleResponse, error) {
 	defer res.Body.Close()
 	data := new(TwitterRuleResponse)
 	json.NewDecoder(res.Body).Decode(data)
+	t.checkClockSkew(data)
+	t.scope(data)
 
 	return data, nil
 }
 
+// GetRulesGrouped will fetch the current rules and group them by their tag.
+// Rules without a tag are grouped under the empty string key "".
+func (t *rules) GetRulesGrouped() (map[string][]DataRule, error) {
+	res, err := t.Get()
+
+	if err != nil {
+		return nil, err
+	}
+
+	grouped := make(map[string][]DataRule)
+	for _, rule := range res.Data {
+		grouped[rule.Tag] = append(grouped[rule.Tag], rule)
+	}
+
+	return grouped, nil
+}
+
+// TestRule submits a single rule in dry-run mode and reports whether Twitter accepts it.
+// When the rule is invalid, the returned string contains the error title and details from Twitter.
+// Your existing rules are never changed.
+func (t *rules) TestRule(value string) (bool, string, error) {
+	req := NewRuleBuilder().AddRule(value, "").Build()
+	res, err := t.Create(req, true)
+
+	if err != nil {
+		return false, "", err
+	}
+
+	verdict := res.Verdicts(req)[0]
+	if verdict.Valid && len(res.Errors) > 0 {
+		return false, res.Errors[0].reason(), nil
+	}
+
+	return verdict.Valid, verdict.Reason, nil
+}
+
+// merge adds the data, errors, and summary counts of another response to this one.
+func (r *TwitterRuleResponse) merge(other *TwitterRuleResponse) {
+	r.Data = append(r.Data, other.Data...)
+	r.Errors = append(r.Errors, other.Errors...)
+	r.Meta.Sent = other.Meta.Sent
+	r.Meta.Summary.Created += other.Meta.Summary.Created
+	r.Meta.Summary.NotCreated += other.Meta.Summary.NotCreated
+	r.Meta.Summary.Deleted += other.Meta.Summary.Deleted
+	r.Meta.Summary.NotDeleted += other.Meta.Summary.NotDeleted
+}
+
 func (t *rules) addDryRun(dryRun bool) *url.Values {
 	if dryRun {
 		query := new(url.URL).Query()
@@ -2,10 +2,12 @@ package rules
 
 import (
 	"bytes"
+	encodingjson "encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"strings"
 	"testing"
 
 	"dev.freespoke.com/twitter-stream/httpclient"
@@ -287,3 +289,271 @@ func TestGetRules(t *testing.T) {
 		})
 	}
 }
+
+func TestTestRule(t *testing.T) {
+	var tests = []struct {
+		value  string
+		json   string
+		valid  bool
+		reason string
+	}{
+		{
+			"cat has:images",
+			`{"meta": {"sent": "today", "summary": {"created": 1, "not_created": 0}}}`,
+			true,
+			"",
+		},
+		{
+			"cat has:nonsense",
+			`{
+				"meta": {"sent": "today", "summary": {"created": 0, "not_created": 1}},
+				"errors": [{"value": "cat has:nonsense", "title": "UnprocessableEntity", "details": ["Reference to invalid operator"]}]
+			}`,
+			false,
+			"UnprocessableEntity: Reference to invalid operator",
+		},
+	}
+
+	for i, tt := range tests {
+		testName := fmt.Sprintf("TestTestRule (%d)", i)
+
+		t.Run(testName, func(t *testing.T) {
+			mockClient := httpclient.NewHttpClientMock("sometoken")
+			mockClient.MockAddRules = func(queryParams *url.Values, body string) (*http.Response, error) {
+				if queryParams == nil || queryParams.Get("dry_run") != "true" {
+					t.Errorf("got %v, want dry_run=true", queryParams)
+				}
+
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       ioutil.NopCloser(bytes.NewReader([]byte(tt.json))),
+				}, nil
+			}
+
+			instance := NewRules(mockClient)
+			valid, reason, err := instance.TestRule(tt.value)
+
+			if err != nil {
+				t.Errorf("got err %v", err)
+			}
+
+			if valid != tt.valid {
+				t.Errorf("got %v, want %v", valid, tt.valid)
+			}
+
+			if reason != tt.reason {
+				t.Errorf("got %s, want %s", reason, tt.reason)
+			}
+		})
+	}
+}
+
+func TestGetRulesGrouped(t *testing.T) {
+	mockClient := httpclient.NewHttpClientMock("sometoken")
+	mockClient.MockGetRules = func() (*http.Response, error) {
+		json := `{
+			"data": [
+				{"value": "cat has:images", "tag": "cats", "id": "1"},
+				{"value": "kitten", "tag": "cats", "id": "2"},
+				{"value": "dog", "id": "3"}
+			],
+			"meta": {"sent": "today"}
+		}`
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(bytes.NewReader([]byte(json))),
+		}, nil
+	}
+
+	instance := NewRules(mockClient)
+	result, err := instance.GetRulesGrouped()
+
+	if err != nil {
+		t.Errorf("got err %v", err)
+	}
+
+	if len(result) != 2 {
+		t.Errorf("got %d groups, want 2", len(result))
+	}
+
+	if len(result["cats"]) != 2 || result["cats"][1].Id != "2" {
+		t.Errorf("got %v, want both cat rules", result["cats"])
+	}
+
+	if len(result[""]) != 1 || result[""][0].Value != "dog" {
+		t.Errorf("got %v, want untagged dog rule", result[""])
+	}
+}
+
+func TestDeleteAllRulesChunksRequests(t *testing.T) {
+	var data strings.Builder
+	for i := 1; i <= 150; i++ {
+		if i > 1 {
+			data.WriteString(",")
+		}
+		data.WriteString(fmt.Sprintf(`{"value": "rule %d", "id": "%d"}`, i, i))
+	}
+
+	mockClient := httpclient.NewHttpClientMock("sometoken")
+	mockClient.MockGetRules = func() (*http.Response, error) {
+		json := `{"data": [` + data.String() + `], "meta": {"sent": "today"}}`
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(bytes.NewReader([]byte(json))),
+		}, nil
+	}
+
+	var requests []DeleteRulesRequest
+	mockClient.MockAddRules = func(queryParams *url.Values, body string) (*http.Response, error) {
+		req := DeleteRulesRequest{}
+		if err := encodingjson.Unmarshal([]byte(body), &req); err != nil {
+			t.Fatal(err)
+		}
+		requests = append(requests, req)
+
+		json := fmt.Sprintf(`{"meta": {"sent": "today", "summary": {"deleted": %d, "not_deleted": 0}}}`, len(req.Delete.Ids))
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(bytes.NewReader([]byte(json))),
+		}, nil
+	}
+
+	instance := NewRules(mockClient)
+	result, err := instance.DeleteAllRules(false)
+
+	if err != nil {
+		t.Errorf("got err %v", err)
+	}
+
+	if len(requests) != 2 || len(requests[0].Delete.Ids) != 100 || len(requests[1].Delete.Ids) != 50 {
+		t.Errorf("got %v, want chunks of 100 and 50 ids", requests)
+	}
+
+	if requests[1].Delete.Ids[49] != 150 {
+		t.Errorf("got %d, want %d", requests[1].Delete.Ids[49], 150)
+	}
+
+	if result.Meta.Summary.Deleted != 150 {
+		t.Errorf("got %d, want %d", result.Meta.Summary.Deleted, 150)
+	}
+}
+
+func TestDeleteAllRulesWithoutRules(t *testing.T) {
+	mockClient := httpclient.NewHttpClientMock("sometoken")
+	mockClient.MockGetRules = func() (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(bytes.NewReader([]byte(`{"meta": {"sent": "today"}}`))),
+		}, nil
+	}
+
+	instance := NewRules(mockClient)
+	result, err := instance.DeleteAllRules(false)
+
+	if err != nil {
+		t.Errorf("got err %v", err)
+	}
+
+	if result.Meta.Summary.Deleted != 0 {
+		t.Errorf("got %d, want %d", result.Meta.Summary.Deleted, 0)
+	}
+}
+
+func TestDeleteChunksLargeRequests(t *testing.T) {
+	var requests []DeleteRulesRequest
+	mockClient := httpclient.NewHttpClientMock("sometoken")
+	mockClient.MockAddRules = func(queryParams *url.Values, body string) (*http.Response, error) {
+		req := DeleteRulesRequest{}
+		if err := encodingjson.Unmarshal([]byte(body), &req); err != nil {
+			t.Fatal(err)
+		}
+		requests = append(requests, req)
+
+		json := fmt.Sprintf(`{"meta": {"sent": "today", "summary": {"deleted": %d}}, "errors": [{"id": "%d"}]}`,
+			len(req.Delete.Ids), req.Delete.Ids[0])
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(bytes.NewReader([]byte(json))),
+		}, nil
+	}
+
+	instance := NewRules(mockClient, WithChunkSize(2))
+	result, err := instance.Delete(NewDeleteRulesRequest(1, 2, 3, 4, 5), false)
+
+	if err != nil {
+		t.Errorf("got err %v", err)
+	}
+
+	if fmt.Sprint(requests) != "[{{[1 2] []}} {{[3 4] []}} {{[5] []}}]" {
+		t.Errorf("got %v, want chunks of 2, 2 and 1 ids", requests)
+	}
+
+	if result.Meta.Summary.Deleted != 5 || len(result.Errors) != 3 || result.Errors[2].Id != "5" {
+		t.Errorf("got %+v, want the summaries summed and the errors concatenated", result)
+	}
+}
+
+func TestCreateSendsCreateRulesRequestJSON(t *testing.T) {
+	var sent string
+	mockClient := httpclient.NewHttpClientMock("sometoken")
+	mockClient.MockAddRules = func(queryParams *url.Values, body string) (*http.Response, error) {
+		sent = body
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(bytes.NewReader([]byte(`{"meta": {"sent": "today"}}`))),
+		}, nil
+	}
+	req := NewRuleBuilder().AddRule("cat has:images", "cat tweets").Build()
+
+	if _, err := NewRules(mockClient).Create(req, true); err != nil {
+		t.Fatalf("got err %v", err)
+	}
+	body, err := req.JSON()
+
+	if err != nil || string(body) != sent || sent != `{"add":[{"value":"cat has:images","tag":"cat tweets"}]}` {
+		t.Errorf("got %s, %v, want the body that was sent %s", body, err, sent)
+	}
+}
+
+func TestCreateChunksLargeRequests(t *testing.T) {
+	var requests []string
+	mockClient := httpclient.NewHttpClientMock("sometoken")
+	mockClient.MockAddRules = func(queryParams *url.Values, body string) (*http.Response, error) {
+		req := CreateRulesRequest{}
+		if err := encodingjson.Unmarshal([]byte(body), &req); err != nil {
+			t.Fatal(err)
+		}
+		var values []string
+		for _, rule := range req.Add {
+			values = append(values, *rule.Value)
+		}
+		requests = append(requests, strings.Join(values, ","))
+
+		json := fmt.Sprintf(`{"meta": {"sent": "today", "summary": {"created": %d}}, "data": [{"value": "%s"}]}`,
+			len(req.Add), *req.Add[0].Value)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(bytes.NewReader([]byte(json))),
+		}, nil
+	}
+	builder := NewRuleBuilder()
+	for _, value := range []string{"a", "b", "c", "d", "e"} {
+		builder.AddRule(value, value)
+	}
+
+	instance := NewRules(mockClient, WithChunkSize(2))
+	result, err := instance.Create(builder.Build(), false)
+
+	if err != nil {
+		t.Errorf("got err %v", err)
+	}
+
+	if fmt.Sprint(requests) != "[a,b c,d e]" {
+		t.Errorf("got %v, want chunks of 2, 2 and 1 rules", requests)
+	}
+
+	if result.Meta.Summary.Created != 5 || len(result.Data) != 3 || result.Data[2].Value != "e" {
+		t.Errorf("got %+v, want the summaries summed and the data concatenated", result)
+	}
+}
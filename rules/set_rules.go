@@ -0,0 +1,181 @@
+package rules
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrRulesNotCreated is wrapped by the RollbackError of SetRulesWithRollback when Twitter refused to create
+// some of the desired rules, for example because they are invalid. Use errors.Is to detect it.
+var ErrRulesNotCreated = errors.New("desired rules were not created")
+
+// RollbackError is returned by SetRulesWithRollback when it rolled back toward the previous rules.
+// Err is why the desired rules were not created. Restored are the deleted rules that were created again,
+// with their new ids. RollbackErr is nil when every created rule was deleted and every deleted rule was restored.
+type RollbackError struct {
+	Err         error
+	Restored    []DataRule
+	RollbackErr error
+}
+
+// Error implements the error interface.
+func (e *RollbackError) Error() string {
+	if e.RollbackErr != nil {
+		return fmt.Sprintf("setting rules failed: %v, and rolling back failed: %v", e.Err, e.RollbackErr)
+	}
+	return fmt.Sprintf("setting rules failed: %v, rolled back to the previous rules", e.Err)
+}
+
+// Unwrap returns why the desired rules were not created.
+func (e *RollbackError) Unwrap() error {
+	return e.Err
+}
+
+// SetRules makes the current rules match `desired`. Rules are compared by value and tag:
+// current rules that aren't desired are deleted, then desired rules that don't exist yet are created.
+// Rules that already exist are left alone, so calling it again with the same rules only fetches them.
+// A request without rules deletes every rule.
+// The returned response aggregates both the delete and the create responses.
+func (t *rules) SetRules(desired CreateRulesRequest, dryRun bool) (*TwitterRuleResponse, error) {
+	return t.setRules(desired, dryRun, false)
+}
+
+// SetRulesWithRollback is SetRules, except that when the desired rules can't all be created after the stale
+// rules were deleted, it rolls back toward the previous rules: the rules it created are deleted and the deleted
+// rules are created again. It then returns a *RollbackError, which wraps the create error, or
+// ErrRulesNotCreated when Twitter refused some rules.
+//
+// Twitter has no transactions, so this is best-effort:
+//   - the stream only matches the rules left at each step, for the time the rollback takes
+//   - restored rules get new ids, so only their value and tag are the same as before
+//   - the rollback may fail too, e.g. when the rules endpoint is down, see RollbackError.RollbackErr
+//
+// Dry runs change no rules, so nothing is rolled back.
+func (t *rules) SetRulesWithRollback(desired CreateRulesRequest, dryRun bool) (*TwitterRuleResponse, error) {
+	return t.setRules(desired, dryRun, true)
+}
+
+// setRules applies `desired`, rolling back if `rollback` is set and the missing rules can't be created.
+func (t *rules) setRules(desired CreateRulesRequest, dryRun bool, rollback bool) (*TwitterRuleResponse, error) {
+	if len(desired.Add) > 0 {
+		if err := desired.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	desired = t.autoTagged(desired)
+
+	current, err := t.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	wanted := make(map[ruleKey]bool, len(desired.Add))
+	for _, rule := range desired.Add {
+		wanted[newRuleKey(rule)] = true
+	}
+
+	existing := make(map[ruleKey]bool, len(current.Data))
+	var stale []DataRule
+	for _, rule := range current.Data {
+		key := ruleKey{value: rule.Value, tag: rule.Tag}
+		existing[key] = true
+		if !wanted[key] {
+			stale = append(stale, rule)
+		}
+	}
+
+	ids, err := ruleIds(stale)
+	if err != nil {
+		return nil, err
+	}
+
+	aggregated, err := t.deleteIds(ids, dryRun)
+	if err != nil {
+		return aggregated, err
+	}
+
+	missing := CreateRulesRequest{}
+	for _, rule := range desired.Add {
+		if !existing[newRuleKey(rule)] {
+			missing.Add = append(missing.Add, rule)
+		}
+	}
+
+	if len(missing.Add) == 0 {
+		return aggregated, nil
+	}
+
+	rollback = rollback && !dryRun
+	created, err := t.Create(missing, dryRun)
+	if err != nil {
+		if rollback {
+			return aggregated, t.rollback(aggregated, err, stale, nil)
+		}
+		return aggregated, err
+	}
+	aggregated.merge(created)
+
+	if rollback && created.Meta.Summary.NotCreated > 0 {
+		err = fmt.Errorf("%w: %d of %d", ErrRulesNotCreated, created.Meta.Summary.NotCreated, len(missing.Add))
+		return aggregated, t.rollback(aggregated, err, stale, created)
+	}
+
+	return aggregated, nil
+}
+
+// ruleKey identifies a rule by its value and tag.
+type ruleKey struct {
+	value string
+	tag   string
+}
+
+func newRuleKey(rule *RuleValue) ruleKey {
+	var key ruleKey
+	if rule.Value != nil {
+		key.value = *rule.Value
+	}
+	if rule.Tag != nil {
+		key.tag = *rule.Tag
+	}
+	return key
+}
+
+// rollback deletes the rules that were `created` and creates the `stale` rules that were deleted again,
+// merging the responses into `aggregated`. It returns a *RollbackError wrapping `cause`.
+func (t *rules) rollback(aggregated *TwitterRuleResponse, cause error, stale []DataRule, created *TwitterRuleResponse) error {
+	rollbackErr := &RollbackError{Err: cause}
+
+	if created != nil && len(created.Data) > 0 {
+		ids, err := ruleIds(created.Data)
+		if err == nil {
+			var deleted *TwitterRuleResponse
+			if deleted, err = t.deleteIds(ids, false); err == nil {
+				aggregated.merge(deleted)
+			}
+		}
+		if err != nil {
+			rollbackErr.RollbackErr = fmt.Errorf("deleting the created rules: %w", err)
+			return rollbackErr
+		}
+	}
+
+	if len(stale) == 0 {
+		return rollbackErr
+	}
+
+	builder := NewRuleBuilder()
+	for _, rule := range stale {
+		builder.AddRule(rule.Value, rule.Tag)
+	}
+	restored, err := t.Create(builder.Build(), false)
+	if err != nil {
+		rollbackErr.RollbackErr = fmt.Errorf("restoring the deleted rules: %w", err)
+		return rollbackErr
+	}
+	aggregated.merge(restored)
+	rollbackErr.Restored = restored.Data
+	if int(restored.Meta.Summary.Created) < len(stale) {
+		rollbackErr.RollbackErr = fmt.Errorf("restored %d of %d deleted rules", restored.Meta.Summary.Created, len(stale))
+	}
+	return rollbackErr
+}
@@ -0,0 +1,146 @@
+package rules
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"dev.freespoke.com/twitter-stream/httpclient"
+)
+
+func givenRulesClient(current string, requests *[]string) httpclient.IHttpClient {
+	mockClient := httpclient.NewHttpClientMock("sometoken")
+	mockClient.MockGetRules = func() (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(current)),
+		}, nil
+	}
+	mockClient.MockAddRules = func(queryParams *url.Values, body string) (*http.Response, error) {
+		*requests = append(*requests, body)
+
+		res := `{"meta": {"sent": "today", "summary": {"deleted": 1}}}`
+		if strings.Contains(body, `"add"`) {
+			res = `{"meta": {"sent": "today", "summary": {"created": 1}}}`
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(bytes.NewReader([]byte(res))),
+		}, nil
+	}
+	return mockClient
+}
+
+func TestSetRules(t *testing.T) {
+	current := `{"data": [
+		{"value": "cat has:images", "tag": "cats", "id": "1"},
+		{"value": "dog", "tag": "dogs", "id": "2"}
+	], "meta": {"sent": "today"}}`
+
+	var requests []string
+	instance := NewRules(givenRulesClient(current, &requests))
+
+	desired := NewRuleBuilder().AddRule("cat has:images", "cats").AddRule("bird", "birds").Build()
+	result, err := instance.SetRules(desired, false)
+
+	if err != nil {
+		t.Fatalf("got err %v", err)
+	}
+
+	if len(requests) != 2 {
+		t.Fatalf("got %d requests, want a delete and a create", len(requests))
+	}
+
+	deleted := DeleteRulesRequest{}
+	if err := json.Unmarshal([]byte(requests[0]), &deleted); err != nil {
+		t.Fatal(err)
+	}
+	if len(deleted.Delete.Ids) != 1 || deleted.Delete.Ids[0] != 2 {
+		t.Errorf("got %v, want the dog rule deleted", deleted.Delete.Ids)
+	}
+
+	created := CreateRulesRequest{}
+	if err := json.Unmarshal([]byte(requests[1]), &created); err != nil {
+		t.Fatal(err)
+	}
+	if len(created.Add) != 1 || *created.Add[0].Value != "bird" {
+		t.Errorf("got %v, want the bird rule created", requests[1])
+	}
+
+	if result.Meta.Summary.Deleted != 1 || result.Meta.Summary.Created != 1 {
+		t.Errorf("got %+v, want 1 deleted and 1 created", result.Meta.Summary)
+	}
+}
+
+func TestSetRulesWithoutChanges(t *testing.T) {
+	current := `{"data": [{"value": "cat has:images", "tag": "cats", "id": "1"}], "meta": {"sent": "today"}}`
+
+	var requests []string
+	instance := NewRules(givenRulesClient(current, &requests))
+
+	if _, err := instance.SetRules(NewRuleBuilder().AddRule("cat has:images", "cats").Build(), false); err != nil {
+		t.Fatalf("got err %v", err)
+	}
+
+	if len(requests) != 0 {
+		t.Errorf("got %v, want no changes", requests)
+	}
+}
+
+func TestSetRulesWithRollback(t *testing.T) {
+	const (
+		deleted        = `{"meta": {"summary": {"deleted": 1}}}`
+		partlyCreated  = `{"data": [{"value": "bird", "tag": "birds", "id": "10"}], "meta": {"summary": {"created": 1, "not_created": 1}}}`
+		restored       = `{"data": [{"value": "cat", "tag": "cats", "id": "11"}], "meta": {"summary": {"created": 1}}}`
+		notRestored    = `{"meta": {"summary": {"not_created": 1}}}`
+		wantDeleteOld  = `{"delete":{"ids":[1]}}`
+		wantCreate     = `{"add":[{"value":"bird","tag":"birds"},{"value":"bad(","tag":"bad"}]}`
+		wantDeleteNew  = `{"delete":{"ids":[10]}}`
+		wantRestoreOld = `{"add":[{"value":"cat","tag":"cats"}]}`
+	)
+	desired := NewRuleBuilder().AddRule("bird", "birds").AddRule("bad(", "bad").Build()
+
+	var tests = []struct {
+		name        string
+		responses   []string
+		rollbackErr bool
+	}{
+		{"restores the previous rules", []string{deleted, partlyCreated, deleted, restored}, false},
+		{"reports a failed restore", []string{deleted, partlyCreated, deleted, notRestored}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []string
+			instance := NewRules(givenRetagClient(&requests, tt.responses...))
+
+			_, err := instance.SetRulesWithRollback(desired, false)
+
+			var rollbackErr *RollbackError
+			if !errors.As(err, &rollbackErr) || !errors.Is(err, ErrRulesNotCreated) {
+				t.Fatalf("got err %v, want a RollbackError wrapping %v", err, ErrRulesNotCreated)
+			}
+			if (rollbackErr.RollbackErr != nil) != tt.rollbackErr {
+				t.Errorf("got rollback err %v", rollbackErr.RollbackErr)
+			}
+			if fmt.Sprint(requests) != fmt.Sprint([]string{wantDeleteOld, wantCreate, wantDeleteNew, wantRestoreOld}) {
+				t.Errorf("got requests %v", requests)
+			}
+		})
+	}
+
+	t.Run("leaves a dry run alone", func(t *testing.T) {
+		var requests []string
+		instance := NewRules(givenRetagClient(&requests, deleted, partlyCreated))
+
+		if _, err := instance.SetRulesWithRollback(desired, true); err != nil || len(requests) != 2 {
+			t.Errorf("got err %v after %d requests, want no rollback", err, len(requests))
+		}
+	})
+}
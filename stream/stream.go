@@ -1,10 +1,18 @@
 package stream
 
 import (
+	"context"
+	"io"
+	"log"
 	"net/http"
 	"net/url"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"dev.freespoke.com/twitter-stream/httpclient"
+	"dev.freespoke.com/twitter-stream/rules"
 )
 
 type (
@@ -14,15 +22,41 @@ type (
 	// IStream is the interface that the stream struct implements.
 	IStream interface {
 		StartStream(queryParams *url.Values) error
+		StartIDStream(queryParams *url.Values) (<-chan string, error)
 		StopStream()
 		GetMessages() <-chan StreamMessage
 		SetUnmarshalHook(hook UnmarshalHook)
+		CloseOnSignals(signals ...os.Signal)
+		BufferPoolEnabled() bool
+		Stats() Stats
+		TestConnection(queryParams *url.Values, timeout time.Duration) (*StreamMessage, error)
+		OnMessage(handler func(StreamMessage))
+		OnError(handler func(error))
+		WaitConnected(ctx context.Context) error
+		ActiveClient() httpclient.IHttpClient
+		ForceReconnect() error
+		StreamFor(queryParams *url.Values, d time.Duration) error
+		UnmatchedRules(since time.Time) []string
+		Collect(ctx context.Context, queryParams *url.Values, n int) ([]StreamMessage, error)
+		StopWithReport() ShutdownReport
+		Err() error
+		StartStreamContext(ctx context.Context, queryParams *url.Values) error
+		Health() Health
 	}
 
 	// StreamMessage is the message that is sent from the messages channel.
+	// Sequence increases by one for every message read by the stream, starting at 1.
+	// It continues across reconnects of the same Stream. A gap means messages were dropped by
+	// OverflowDropNewest, and the missing numbers are counted in Stats().Dropped.
+	// ReceivedAt is when the stream finished reading the message, with a monotonic clock reading, so the lag
+	// since the tweet's created_at, or while the message waited to be delivered, can be measured.
+	// Closed is only set, on the last message, with WithClosedMessage.
 	StreamMessage struct {
-		Data interface{}
-		Err  error
+		Data       interface{}
+		Err        error
+		Sequence   uint64
+		ReceivedAt time.Time
+		Closed     bool
 	}
 
 	// Stream is the struct that manages a long running TCP connection with Twitter.
@@ -30,25 +64,92 @@ type (
 	// It is highly encouraged to set a unmarshal hook before starting a stream. Unmarshaling json
 	// in a separate goroutine is not recommended because the Go bytes.Buffer is not goroutine safe.
 	Stream struct {
-		unmarshalHook UnmarshalHook
-		messages      chan StreamMessage
-		httpClient    httpclient.IHttpClient
-		done          chan struct{}
-		reader        IStreamResponseBodyReader
+		unmarshalHook         UnmarshalHook
+		messages              chan StreamMessage
+		httpClient            httpclient.IHttpClient
+		clients               []httpclient.IHttpClient
+		clientIndex           int
+		clientMu              sync.Mutex
+		autoReconnect         bool
+		retryUnauthorized     bool
+		freshConnection       bool
+		waiting               int32
+		wake                  chan struct{}
+		finished              chan struct{}
+		terminalErr           error
+		canceled              error
+		errMu                 sync.Mutex
+		started               int32
+		managedRules          *rules.CreateRulesRequest
+		done                  chan struct{}
+		reader                IStreamResponseBodyReader
+		initialConnectRetries int
+		firstDataTimeout      time.Duration
+		dataWatchdog          time.Duration
+		decodeWorkers         int
+		unorderedDecode       bool
+		bufferPool            *sync.Pool
+		pendingBuffer         *[]byte
+		countTags             bool
+		tagFilter             map[string]bool
+		dedup                 *idWindow
+		sanitizeUTF8          bool
+		lineProcessor         func([]byte) []byte
+		dataOnly              bool
+		rawSink               *rawSink
+		tagArchive            *tagArchive
+		broadcaster           *Broadcaster
+		disallowUnknownFields bool
+		closedMessage         bool
+		replaySpeed           float64
+		maxMessageSize        int
+		messageBuffer         int
+		maxBufferedBytes      int
+		maxMessageRate        int
+		overflowPolicy        OverflowPolicy
+		outbox                *outbox
+		onMessage             func(StreamMessage)
+		onError               func(error)
+		stats                 streamStats
+		backoff               Backoff
+		reasonBackoff         ReasonBackoff
+		lastWarning           *StreamWarning
+		trailer               http.Header
+		sequence              uint64
+		connected             connectState
+		stopOnce              sync.Once
+		signalOnce            sync.Once
+		abandonOnce           sync.Once
+		abandoned             chan struct{}
+		bodyMu                sync.Mutex
+		body                  io.Closer
 	}
 )
 
 // NewStream creates an instance of `Stream`. This is used to manage the stream with Twitter.
-func NewStream(httpClient httpclient.IHttpClient, reader IStreamResponseBodyReader) IStream {
-	return &Stream{
+func NewStream(httpClient httpclient.IHttpClient, reader IStreamResponseBodyReader, opts ...Option) IStream {
+	s := &Stream{
 		unmarshalHook: func(bytes []byte) (interface{}, error) {
 			return bytes, nil
 		},
 		messages:   make(chan StreamMessage),
 		done:       make(chan struct{}),
+		wake:       make(chan struct{}, 1),
+		finished:   make(chan struct{}),
+		abandoned:  make(chan struct{}),
 		reader:     reader,
 		httpClient: httpClient,
+		backoff:    NewExponentialBackoff(),
 	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+	if limiter, ok := s.reader.(messageSizeLimiter); ok && s.maxMessageSize > 0 {
+		limiter.setMaxMessageSize(s.maxMessageSize)
+	}
+
+	return s
 }
 
 // SetUnmarshalHook sets the function that unmarshals json. It is highly encouraged
@@ -58,14 +159,25 @@ func (s *Stream) SetUnmarshalHook(hook UnmarshalHook) {
 	s.unmarshalHook = hook
 }
 
-// GetMessages returns the read-only messages channel
+// GetMessages returns the read-only messages channel.
+// It is the same channel for the whole life of the Stream: reconnects made by WithAutoReconnect keep delivering
+// on it, and it is only closed once the stream stops for good, so a single range loop reads every message.
 func (s *Stream) GetMessages() <-chan StreamMessage {
 	return s.messages
 }
 
 // StopStream sends a close signal to stop the stream of tweets.
+// It closes the response body so a pending read returns immediately. Calling it more than once is a no-op.
 func (s *Stream) StopStream() {
-	close(s.done)
+	s.stopOnce.Do(func() {
+		close(s.done)
+
+		s.bodyMu.Lock()
+		defer s.bodyMu.Unlock()
+		if s.body != nil {
+			s.body.Close()
+		}
+	})
 }
 
 // StartStream makes an HTTP GET request to twitter and starts streaming tweets to the Messages channel.
@@ -73,43 +185,190 @@ func (s *Stream) StopStream() {
 // See available query params here https://developer.twitter.com/en/docs/twitter-api/tweets/filtered-stream/api-reference/get-tweets-search-stream.
 // See an example here: https://developer.twitter.com/en/docs/twitter-api/expansions.
 func (s *Stream) StartStream(optionalQueryParams *url.Values) error {
-	res, err := s.httpClient.GetSearchStream(optionalQueryParams)
+	if err := s.validateAuthFields(optionalQueryParams); err != nil {
+		s.connected.settle(err)
+		return err
+	}
+
+	res, err := s.connect(optionalQueryParams)
+
+	if err == nil {
+		if err = s.applyManagedRules(); err != nil {
+			res.Body.Close()
+		}
+	}
 
 	if err != nil {
+		s.connected.settle(err)
 		return err
 	}
 
-	s.reader.setStreamResponseBody(res.Body)
+	s.setBody(res)
+	s.connected.settle(nil)
 
-	go s.streamMessages(res)
+	atomic.StoreInt32(&s.started, 1)
+	go s.streamMessages(optionalQueryParams)
 
 	return nil
 }
 
-func (s *Stream) streamMessages(res *http.Response) {
-	defer res.Body.Close()
+// connect makes the HTTP GET request to twitter, retrying up to `initialConnectRetries` times on non-fatal errors.
+// Waiting between retries ends early if the stream is stopped, returning the last error.
+func (s *Stream) connect(queryParams *url.Values) (*http.Response, error) {
+	res, err := s.dial(queryParams)
+
+	for attempt := 0; err != nil && attempt < s.initialConnectRetries && !s.isFatal(err); attempt++ {
+		delay := s.backoff.NextDelay(attempt)
+		log.Printf("Failed to start stream: %v. Retrying in %v", err, delay)
+		if !s.sleep(delay) {
+			// StopStream was called while waiting
+			break
+		}
+
+		res, err = s.dial(queryParams)
+	}
+	if err == nil {
+		s.backoff.Reset()
+	}
+
+	return res, err
+}
+
+func (s *Stream) streamMessages(queryParams *url.Values) {
+	defer close(s.finished)
+	defer s.closeBody()
 	defer close(s.messages)
 
+	outbox := newOutbox(s)
+	pool := newDecodePool(s)
+	err := s.readMessages(pool)
+	s.stats.setDisconnected(err)
+	for err != nil && s.autoReconnect {
+		log.Printf("Stream disconnected: %v", err)
+		if err = s.reconnect(queryParams); err != nil {
+			s.stats.setDisconnected(err)
+			break
+		}
+		err = s.readMessages(pool)
+		s.stats.setDisconnected(err)
+	}
+	pool.close()
+	if s.rawSink != nil {
+		s.rawSink.close()
+	}
+	if s.tagArchive != nil {
+		s.tagArchive.close()
+	}
+	if s.broadcaster != nil {
+		s.broadcaster.close()
+	}
+	s.setTerminalErr(err)
+
+	if err != nil && !s.closedMessage {
+		s.deliver(StreamMessage{
+			Data:       nil,
+			Err:        err,
+			Sequence:   s.nextSequence(),
+			ReceivedAt: time.Now(),
+		})
+	}
+
+	outbox.close()
+	if s.closedMessage {
+		s.sendClosed()
+	}
+	if err != nil {
+		s.StopStream()
+	}
+}
+
+// readMessages reads messages until the stream is stopped or the read fails.
+// It returns the read error, or nil if the stream was stopped.
+func (s *Stream) readMessages(pool *decodePool) error {
+	firstData := s.watchConnection(s.firstDataTimeout, ErrNoDataAfterConnect)
+	watchdog := s.watchConnection(s.dataWatchdog, ErrDataWatchdog)
+	// the watches must not outlive this connection, or they would close the next one
+	defer firstData.stop()
+	defer watchdog.stop()
+
+	s.lastWarning = nil
 	for !stopped(s.done) {
 		b, err := s.reader.readNext()
+		receivedAt := time.Now()
 		if err != nil {
-			s.messages <- StreamMessage{
-				Data: nil,
-				Err:  err,
+			if stopped(s.done) {
+				// the body was closed by StopStream
+				return nil
 			}
-			s.StopStream()
-			break
+			return watchdog.wrap(firstData.wrap(s.closeReason(err)))
+		}
+		if firstData != nil {
+			firstData.stop()
+			firstData = nil
+		}
+		if s.rawSink != nil {
+			s.rawSink.write(b)
 		}
 		if len(b) == 0 {
 			// empty keep-alive
 			continue
 		}
+		watchdog.reset()
+		s.stats.setLastMessage(receivedAt)
 
-		data, err := s.unmarshalHook(b)
+		if s.sanitizeUTF8 {
+			b = s.sanitize(b)
+		}
+
+		if s.lineProcessor != nil {
+			if b = s.lineProcessor(b); len(b) == 0 {
+				continue
+			}
+		}
 
-		s.messages <- StreamMessage{
-			Data: data,
-			Err:  err,
+		if warning := parseWarning(b); warning != nil {
+			s.lastWarning = warning
+			pool.deliver(StreamMessage{
+				Data:       nil,
+				Err:        warning,
+				Sequence:   s.nextSequence(),
+				ReceivedAt: receivedAt,
+			})
+			continue
+		}
+
+		if s.dedup != nil && s.isDuplicate(b) {
+			continue
 		}
+
+		if s.tagFilter != nil && !s.matchesTagFilter(b) {
+			continue
+		}
+
+		if s.countTags {
+			s.stats.countTags(b)
+		}
+
+		if s.tagArchive != nil {
+			s.tagArchive.write(b)
+		}
+
+		if s.broadcaster != nil {
+			s.broadcaster.publish(b)
+		}
+
+		if s.dataOnly {
+			b = unwrapData(b)
+		}
+
+		pool.decode(b, s.nextSequence(), receivedAt)
 	}
+	return nil
+}
+
+// nextSequence returns the sequence number of the next message read. It is only called by the read goroutine,
+// so the numbers follow the order messages were read in, however they are decoded and delivered.
+func (s *Stream) nextSequence() uint64 {
+	s.sequence++
+	return s.sequence
 }
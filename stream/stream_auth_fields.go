@@ -0,0 +1,65 @@
+package stream
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ErrUserContextRequired is returned by StartStream when fields that need user-context auth are requested
+// with an app-only bearer token. Twitter rejects the whole stream in that case, so they are rejected before
+// connecting. Use `httpclient.WithOAuth1` to stream with user context. Use errors.Is to detect it.
+var ErrUserContextRequired = errors.New("requested fields require user-context auth")
+
+// userContextFields are the tweet.fields and media.fields values that app-only auth can't access.
+var userContextFields = map[string]bool{
+	"non_public_metrics": true,
+	"organic_metrics":    true,
+	"promoted_metrics":   true,
+}
+
+// userContextClient is implemented by http clients that can authenticate with user context,
+// such as clients created with `httpclient.WithOAuth1`.
+type userContextClient interface {
+	UserContext() bool
+}
+
+// UserContextFields returns the requested tweet and media fields that require user-context auth,
+// such as "organic_metrics" and "promoted_metrics".
+func (s *StreamQueryParamBuilder) UserContextFields() []string {
+	var fields []string
+	for _, list := range [][]*string{s.tweetFields, s.mediaFields} {
+		for _, field := range list {
+			if userContextFields[*field] {
+				fields = append(fields, *field)
+			}
+		}
+	}
+	return fields
+}
+
+// validateAuthFields returns ErrUserContextRequired if the query params request user-context fields
+// and the client only has app-only auth.
+func (s *Stream) validateAuthFields(queryParams *url.Values) error {
+	if client, ok := s.client().(userContextClient); ok && client.UserContext() {
+		return nil
+	}
+	if queryParams == nil {
+		return nil
+	}
+
+	var fields []string
+	for _, param := range []string{"tweet.fields", "media.fields"} {
+		for _, field := range strings.Split(queryParams.Get(param), ",") {
+			if userContextFields[field] {
+				fields = append(fields, param+"="+field)
+			}
+		}
+	}
+
+	if len(fields) > 0 {
+		return fmt.Errorf("%w: %s can not be requested with an app-only bearer token", ErrUserContextRequired, strings.Join(fields, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package stream
+
+import (
+	"errors"
+	"testing"
+
+	"dev.freespoke.com/twitter-stream/httpclient"
+)
+
+func TestUserContextFields(t *testing.T) {
+	builder := NewStreamQueryParamsBuilder().(*StreamQueryParamBuilder)
+	builder.AddTweetField("created_at").AddTweetField("organic_metrics").AddMediaField("promoted_metrics")
+
+	fields := builder.UserContextFields()
+
+	if len(fields) != 2 || fields[0] != "organic_metrics" || fields[1] != "promoted_metrics" {
+		t.Errorf("got %v, want [organic_metrics promoted_metrics]", fields)
+	}
+}
+
+func TestStartStreamRejectsUserContextFieldsWithAppOnlyAuth(t *testing.T) {
+	query := NewStreamQueryParamsBuilder().AddTweetField("created_at").AddTweetField("promoted_metrics").Build()
+
+	instance := NewStream(httpclient.NewHttpClientMock("foobar"), NewStreamResponseBodyReader())
+	err := instance.StartStream(query)
+
+	if !errors.Is(err, ErrUserContextRequired) {
+		t.Errorf("got %v, want ErrUserContextRequired", err)
+	}
+}
+
+func TestStartStreamAllowsPublicFields(t *testing.T) {
+	query := NewStreamQueryParamsBuilder().AddTweetField("created_at").AddTweetField("public_metrics").Build()
+
+	instance := NewStream(givenStreamClient(""), NewStreamResponseBodyReader())
+	defer instance.StopStream()
+
+	if err := instance.StartStream(query); err != nil {
+		t.Errorf("got %v, want nil", err)
+	}
+}
+
+type userContextMock struct {
+	httpclient.IHttpClient
+}
+
+func (userContextMock) UserContext() bool {
+	return true
+}
+
+func TestStartStreamAllowsUserContextFieldsWithUserContext(t *testing.T) {
+	query := NewStreamQueryParamsBuilder().AddTweetField("organic_metrics").Build()
+
+	instance := NewStream(userContextMock{givenStreamClient("")}, NewStreamResponseBodyReader())
+	defer instance.StopStream()
+
+	if err := instance.StartStream(query); err != nil {
+		t.Errorf("got %v, want nil", err)
+	}
+}
@@ -0,0 +1,77 @@
+package stream
+
+import (
+	"math/rand"
+	"time"
+)
+
+// maxBackoff is the longest delay the default backoff will return.
+const maxBackoff = 30 * time.Second
+
+type (
+	// Backoff decides how long to wait before retrying a connection. NextDelay is called with the attempt,
+	// starting at 0 for the first retry after a disconnect, and Reset is called once a connection succeeded,
+	// so strategies that keep state, like decorrelated jitter, can start over.
+	// It is only called from one goroutine at a time.
+	Backoff interface {
+		NextDelay(attempt int) time.Duration
+		Reset()
+	}
+
+	// BackoffFunc adapts a function of the attempt into a stateless Backoff, e.g. for a fixed schedule.
+	BackoffFunc func(attempt int) time.Duration
+
+	// ExponentialBackoff doubles the delay every attempt, from Initial up to Max, with a random jitter of up to
+	// half the delay so many clients don't retry in lockstep. A Max of 0 or less caps the delay at 30 seconds, like
+	// NewExponentialBackoff, and a negative Initial is treated as 0.
+	ExponentialBackoff struct {
+		Initial time.Duration
+		Max     time.Duration
+	}
+)
+
+// WithBackoff sets how long to wait between connection attempts, for the retries of WithInitialConnectRetries and
+// the reconnects of WithAutoReconnect. Defaults to NewExponentialBackoff.
+func WithBackoff(backoff Backoff) Option {
+	return func(s *Stream) {
+		s.backoff = backoff
+	}
+}
+
+// NextDelay implements Backoff.
+func (f BackoffFunc) NextDelay(attempt int) time.Duration {
+	return f(attempt)
+}
+
+// Reset implements Backoff. A BackoffFunc has no state, so it does nothing.
+func (f BackoffFunc) Reset() {}
+
+// NewExponentialBackoff returns the default backoff, starting at 1 second and capped at 30 seconds.
+func NewExponentialBackoff() *ExponentialBackoff {
+	return &ExponentialBackoff{Initial: time.Second, Max: maxBackoff}
+}
+
+// NextDelay implements Backoff.
+func (b *ExponentialBackoff) NextDelay(attempt int) time.Duration {
+	limit := b.Max
+	if limit <= 0 {
+		limit = maxBackoff
+	}
+
+	delay := b.Initial
+	for i := 0; i < attempt && delay < limit; i++ {
+		delay *= 2
+	}
+	if delay > limit {
+		delay = limit
+	}
+	if delay < 0 {
+		delay = 0
+	}
+
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// Reset implements Backoff. The delay only depends on the attempt, so it does nothing.
+func (b *ExponentialBackoff) Reset() {}
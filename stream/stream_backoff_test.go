@@ -0,0 +1,79 @@
+package stream
+
+import (
+	"testing"
+	"time"
+
+	"dev.freespoke.com/twitter-stream/httpclient"
+)
+
+// recordingBackoff records the attempts it was asked about and how often it was reset.
+type recordingBackoff struct {
+	attempts []int
+	resets   int
+}
+
+func (b *recordingBackoff) NextDelay(attempt int) time.Duration {
+	b.attempts = append(b.attempts, attempt)
+	return 0
+}
+
+func (b *recordingBackoff) Reset() {
+	b.resets++
+}
+
+func TestWithBackoffIsUsedToReconnect(t *testing.T) {
+	backoff := &recordingBackoff{}
+	instance := NewStream(givenReconnectingClient(3, httpclient.ErrConnectionLimit), NewStreamResponseBodyReader(),
+		WithAutoReconnect(), WithBackoff(backoff))
+
+	drain(t, instance)
+
+	if len(backoff.attempts) != 3 {
+		t.Errorf("got attempts %v, want one per reconnect", backoff.attempts)
+	}
+	for _, attempt := range backoff.attempts {
+		if attempt != 0 {
+			t.Errorf("got attempts %v, want every reconnect to succeed or fail on its first attempt", backoff.attempts)
+		}
+	}
+	if backoff.resets != 3 {
+		t.Errorf("got %d resets, want one for the first connection and one per successful reconnect", backoff.resets)
+	}
+}
+
+func TestExponentialBackoffDoubles(t *testing.T) {
+	backoff := &ExponentialBackoff{Initial: 100 * time.Millisecond, Max: time.Second}
+
+	for attempt, want := range []time.Duration{100, 200, 400, 800, 1000, 1000} {
+		want *= time.Millisecond
+		delay := backoff.NextDelay(attempt)
+		if delay < want/2 || delay > want {
+			t.Errorf("got %v for attempt %d, want within [%v, %v]", delay, attempt, want/2, want)
+		}
+	}
+}
+
+func TestExponentialBackoffBounds(t *testing.T) {
+	tests := []struct {
+		name     string
+		backoff  *ExponentialBackoff
+		attempt  int
+		min, max time.Duration
+	}{
+		{"zero max uses the default cap", &ExponentialBackoff{Initial: time.Second}, 3, 4 * time.Second, 8 * time.Second},
+		{"zero max caps at the default", &ExponentialBackoff{Initial: time.Second}, 20, maxBackoff / 2, maxBackoff},
+		{"negative max uses the default cap", &ExponentialBackoff{Initial: time.Second, Max: -time.Second}, 0, time.Second / 2, time.Second},
+		{"negative initial is clamped to zero", &ExponentialBackoff{Initial: -time.Second, Max: time.Second}, 2, 0, 0},
+		{"zero initial and max", &ExponentialBackoff{}, 5, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delay := tt.backoff.NextDelay(tt.attempt)
+			if delay < tt.min || delay > tt.max {
+				t.Errorf("got %v, want within [%v, %v]", delay, tt.min, tt.max)
+			}
+		})
+	}
+}
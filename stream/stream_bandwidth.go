@@ -0,0 +1,63 @@
+package stream
+
+import (
+	"io"
+	"time"
+)
+
+// bandwidthWindow is how many whole seconds Stats().BytesPerSecond is averaged over.
+const bandwidthWindow = 10
+
+type (
+	// bandwidth is a ring of per-second byte counts, the current second and the window before it,
+	// so the rolling rate costs a few additions to keep up.
+	bandwidth struct {
+		seconds [bandwidthWindow + 1]int64
+		bytes   [bandwidthWindow + 1]uint64
+	}
+
+	// countingReader counts the bytes read from the connection into the stream's stats.
+	countingReader struct {
+		reader io.Reader
+		stats  *streamStats
+	}
+)
+
+func (c countingReader) Read(p []byte) (int, error) {
+	n, err := c.reader.Read(p)
+	if n > 0 {
+		c.stats.addBytes(n, time.Now())
+	}
+	return n, err
+}
+
+// add counts `n` bytes read at `now`.
+func (b *bandwidth) add(n int, now time.Time) {
+	second := now.Unix()
+	i := second % (bandwidthWindow + 1)
+	if b.seconds[i] != second {
+		b.seconds[i] = second
+		b.bytes[i] = 0
+	}
+	b.bytes[i] += uint64(n)
+}
+
+// rate returns the average bytes per second over the last bandwidthWindow whole seconds before `now`.
+func (b *bandwidth) rate(now time.Time) float64 {
+	second := now.Unix()
+	var total uint64
+	for i, s := range b.seconds {
+		if s < second && s >= second-bandwidthWindow {
+			total += b.bytes[i]
+		}
+	}
+	return float64(total) / bandwidthWindow
+}
+
+// addBytes counts bytes read from the connection.
+func (st *streamStats) addBytes(n int, now time.Time) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.bytes += uint64(n)
+	st.bandwidth.add(n, now)
+}
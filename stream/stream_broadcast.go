@@ -0,0 +1,154 @@
+package stream
+
+import (
+	"net/http"
+	"sync"
+	"sync/atomic"
+)
+
+// Broadcaster serves the tweets of a stream to any number of HTTP clients as newline delimited JSON, turning one
+// connection to Twitter into a local fan-out service. Attach it to a stream with WithBroadcaster and serve it
+// with net/http, e.g. `http.Handle("/stream", broadcaster)`. Every client gets the tweets read after it connected.
+type Broadcaster struct {
+	buffer  int
+	policy  OverflowPolicy
+	mu      sync.Mutex
+	clients map[*broadcastClient]struct{}
+	done    chan struct{}
+	once    sync.Once
+	dropped uint64
+}
+
+// broadcastClient is the queue of lines of a connected client.
+type broadcastClient struct {
+	lines chan []byte
+	gone  chan struct{}
+}
+
+// NewBroadcaster creates a Broadcaster that buffers up to `buffer` tweets per client. `policy` decides what
+// happens when a client is too slow to keep up: OverflowDropNewest discards the tweets that don't fit, counted
+// in Dropped, while OverflowBlock waits for the client, which holds up reading the stream, and every other client,
+// until the client catches up or disconnects. Prefer OverflowDropNewest unless every client must get every tweet.
+func NewBroadcaster(buffer int, policy OverflowPolicy) *Broadcaster {
+	return &Broadcaster{
+		buffer:  buffer,
+		policy:  policy,
+		clients: make(map[*broadcastClient]struct{}),
+		done:    make(chan struct{}),
+	}
+}
+
+// WithBroadcaster publishes every tweet read by the stream, as read, to `b`. Warnings and keep-alives are not
+// published. Publishing happens on the read goroutine. Once the stream stops, `b` disconnects its clients and
+// answers new ones with 503 Service Unavailable.
+func WithBroadcaster(b *Broadcaster) Option {
+	return func(s *Stream) {
+		s.broadcaster = b
+	}
+}
+
+// ServeHTTP streams tweets to the client until it disconnects or the stream stops.
+func (b *Broadcaster) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	client := &broadcastClient{lines: make(chan []byte, b.buffer), gone: make(chan struct{})}
+	if !b.add(client) {
+		http.Error(w, "the stream has stopped", http.StatusServiceUnavailable)
+		return
+	}
+	defer b.remove(client)
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case line := <-client.lines:
+			if _, err := w.Write(line); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		case <-b.done:
+			return
+		}
+	}
+}
+
+// Clients returns how many clients are connected.
+func (b *Broadcaster) Clients() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.clients)
+}
+
+// Dropped returns how many tweets were discarded by OverflowDropNewest, counting each client separately.
+func (b *Broadcaster) Dropped() uint64 {
+	return atomic.LoadUint64(&b.dropped)
+}
+
+// add registers a client, unless the broadcaster was closed.
+func (b *Broadcaster) add(client *broadcastClient) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if stopped(b.done) {
+		return false
+	}
+	b.clients[client] = struct{}{}
+	return true
+}
+
+// remove unregisters a client, releasing a publish waiting for it.
+func (b *Broadcaster) remove(client *broadcastClient) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.clients, client)
+	close(client.gone)
+}
+
+// publish sends a copy of the tweet, with a trailing newline, to every client.
+func (b *Broadcaster) publish(tweet []byte) {
+	b.mu.Lock()
+	clients := make([]*broadcastClient, 0, len(b.clients))
+	for client := range b.clients {
+		clients = append(clients, client)
+	}
+	b.mu.Unlock()
+	if len(clients) == 0 {
+		return
+	}
+
+	line := append(append(make([]byte, 0, len(tweet)+1), tweet...), '\n')
+	for _, client := range clients {
+		select {
+		case client.lines <- line:
+			continue
+		default:
+		}
+
+		if b.policy == OverflowDropNewest {
+			atomic.AddUint64(&b.dropped, 1)
+			continue
+		}
+		select {
+		case client.lines <- line:
+		case <-client.gone:
+		case <-b.done:
+		}
+	}
+}
+
+// close disconnects every client, and makes the broadcaster refuse new ones.
+func (b *Broadcaster) close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.once.Do(func() {
+		close(b.done)
+	})
+}
@@ -0,0 +1,126 @@
+package stream
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"dev.freespoke.com/twitter-stream/httpclient"
+)
+
+func givenPipeStreamClient(writer **io.PipeWriter) httpclient.IHttpClient {
+	mockClient := httpclient.NewHttpClientMock("foobar")
+	reader, w := io.Pipe()
+	*writer = w
+	mockClient.MockGetSearchStream = func(queryParams *url.Values) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Body: reader}, nil
+	}
+	return mockClient
+}
+
+func waitForClients(t *testing.T, b *Broadcaster, want int) {
+	deadline := time.Now().Add(time.Second)
+	for b.Clients() != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("got %d clients, want %d", b.Clients(), want)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestBroadcaster(t *testing.T) {
+	t.Run("streams tweets to every client", func(t *testing.T) {
+		var writer *io.PipeWriter
+		broadcaster := NewBroadcaster(10, OverflowDropNewest)
+		instance := NewStream(givenPipeStreamClient(&writer), NewStreamResponseBodyReader(), WithBroadcaster(broadcaster))
+		if err := instance.StartStream(nil); err != nil {
+			t.Fatalf("got err when starting stream %v", err)
+		}
+		go func() {
+			for range instance.GetMessages() {
+			}
+		}()
+		server := httptest.NewServer(broadcaster)
+		defer server.Close()
+
+		var readers []*bufio.Reader
+		for i := 0; i < 2; i++ {
+			res, err := http.Get(server.URL)
+			if err != nil {
+				t.Fatalf("got err when connecting %v", err)
+			}
+			defer res.Body.Close()
+			if got := res.Header.Get("Content-Type"); got != "application/x-ndjson" {
+				t.Errorf("got content type %q", got)
+			}
+			readers = append(readers, bufio.NewReader(res.Body))
+		}
+		waitForClients(t, broadcaster, 2)
+
+		writer.Write([]byte("{\"data\":{\"id\":\"1\"}}\r\n\r\n{\"data\":{\"id\":\"2\"}}\r\n"))
+		for i, reader := range readers {
+			for _, want := range []string{"{\"data\":{\"id\":\"1\"}}\n", "{\"data\":{\"id\":\"2\"}}\n"} {
+				if got, err := reader.ReadString('\n'); err != nil || got != want {
+					t.Errorf("client %d: got %q, %v, want %q", i, got, err, want)
+				}
+			}
+		}
+
+		instance.StopStream()
+		writer.Close()
+		for i, reader := range readers {
+			if _, err := reader.ReadString('\n'); err != io.EOF {
+				t.Errorf("client %d: got %v after the stream stopped, want EOF", i, err)
+			}
+		}
+		waitForClients(t, broadcaster, 0)
+
+		res, err := http.Get(server.URL)
+		if err != nil {
+			t.Fatalf("got err when connecting %v", err)
+		}
+		res.Body.Close()
+		if res.StatusCode != http.StatusServiceUnavailable {
+			t.Errorf("got status %d after the stream stopped, want %d", res.StatusCode, http.StatusServiceUnavailable)
+		}
+	})
+
+	t.Run("drops tweets for a slow client", func(t *testing.T) {
+		broadcaster := NewBroadcaster(1, OverflowDropNewest)
+		slow := &broadcastClient{lines: make(chan []byte, 1), gone: make(chan struct{})}
+		broadcaster.add(slow)
+
+		broadcaster.publish([]byte("1"))
+		broadcaster.publish([]byte("2"))
+
+		if got := string(<-slow.lines); got != "1\n" {
+			t.Errorf("got %q, want the first tweet", got)
+		}
+		if got := broadcaster.Dropped(); got != 1 {
+			t.Errorf("got %d dropped, want 1", got)
+		}
+	})
+
+	t.Run("a blocked publish is released when the client disconnects", func(t *testing.T) {
+		broadcaster := NewBroadcaster(0, OverflowBlock)
+		slow := &broadcastClient{lines: make(chan []byte), gone: make(chan struct{})}
+		broadcaster.add(slow)
+
+		published := make(chan struct{})
+		go func() {
+			broadcaster.publish([]byte("1"))
+			close(published)
+		}()
+		broadcaster.remove(slow)
+
+		select {
+		case <-published:
+		case <-time.After(time.Second):
+			t.Fatal("publish still blocked after the client disconnected")
+		}
+	})
+}
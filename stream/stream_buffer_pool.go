@@ -0,0 +1,46 @@
+package stream
+
+import "sync"
+
+// defaultBufferCapacity is the capacity of new buffers in the buffer pool, large enough for most tweets.
+const defaultBufferCapacity = 4096
+
+// WithBufferPool copies every message read from the stream into a buffer taken from a sync.Pool, instead of
+// handing the unmarshal hook the reader's internal buffer, or allocating a fresh copy when WithDecodeWorkers is used.
+//
+// Lifetime contract: the bytes passed to the unmarshal hook, and the `[]byte` Data delivered by the default hook,
+// are only valid until the next message is received from the messages channel. The buffer is then returned
+// to the pool and reused. Copy the bytes if you need to keep them for longer.
+func WithBufferPool() Option {
+	return func(s *Stream) {
+		s.bufferPool = &sync.Pool{
+			New: func() interface{} {
+				b := make([]byte, 0, defaultBufferCapacity)
+				return &b
+			},
+		}
+	}
+}
+
+// BufferPoolEnabled returns true if the stream copies messages into pooled buffers. See WithBufferPool.
+func (s *Stream) BufferPoolEnabled() bool {
+	return s.bufferPool != nil
+}
+
+// copyToBuffer copies b into a pooled buffer. It returns nil if the buffer pool is disabled.
+func (s *Stream) copyToBuffer(b []byte) *[]byte {
+	if s.bufferPool == nil {
+		return nil
+	}
+
+	buffer := s.bufferPool.Get().(*[]byte)
+	*buffer = append((*buffer)[:0], b...)
+	return buffer
+}
+
+// releaseBuffer returns a buffer to the pool once the consumer has moved on from the message using it.
+func (s *Stream) releaseBuffer(buffer *[]byte) {
+	if buffer != nil && s.bufferPool != nil {
+		s.bufferPool.Put(buffer)
+	}
+}
@@ -0,0 +1,68 @@
+package stream
+
+import (
+	"testing"
+
+	"dev.freespoke.com/twitter-stream/httpclient"
+)
+
+func TestBufferPoolEnabled(t *testing.T) {
+	client := httpclient.NewHttpClientMock("foobar")
+
+	if NewStream(client, NewStreamResponseBodyReader()).BufferPoolEnabled() {
+		t.Error("expected buffer pool to be disabled by default")
+	}
+
+	if !NewStream(client, NewStreamResponseBodyReader(), WithBufferPool()).BufferPoolEnabled() {
+		t.Error("expected buffer pool to be enabled")
+	}
+}
+
+func TestBufferPoolDeliversEveryMessage(t *testing.T) {
+	for _, opts := range [][]Option{
+		{WithBufferPool()},
+		{WithBufferPool(), WithDecodeWorkers(4)},
+	} {
+		instance := NewStream(givenNumberedStreamClient(100), NewStreamResponseBodyReader(), opts...)
+		instance.SetUnmarshalHook(givenSlowNumberHook)
+
+		result := collectNumbers(t, instance)
+
+		if len(result) != 100 {
+			t.Fatalf("got %d messages, want 100", len(result))
+		}
+
+		for i, n := range result {
+			if n != i+1 {
+				t.Fatalf("got %d at position %d, want %d", n, i, i+1)
+			}
+		}
+	}
+}
+
+func benchmarkDecodeWorkers(b *testing.B, opts ...Option) {
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		instance := NewStream(givenNumberedStreamClient(1000), NewStreamResponseBodyReader(), opts...)
+		instance.SetUnmarshalHook(func(bytes []byte) (interface{}, error) {
+			return nil, nil
+		})
+		b.StartTimer()
+
+		if err := instance.StartStream(nil); err != nil {
+			b.Fatal(err)
+		}
+		for range instance.GetMessages() {
+		}
+	}
+}
+
+func BenchmarkDecodeWorkersWithoutBufferPool(b *testing.B) {
+	benchmarkDecodeWorkers(b, WithDecodeWorkers(2))
+}
+
+func BenchmarkDecodeWorkersWithBufferPool(b *testing.B) {
+	benchmarkDecodeWorkers(b, WithDecodeWorkers(2), WithBufferPool())
+}
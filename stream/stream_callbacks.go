@@ -0,0 +1,33 @@
+package stream
+
+// OnMessage registers a function that receives messages instead of the messages channel.
+// Errors are also passed to it, unless a function is registered with OnError.
+//
+// The function is called synchronously on the goroutine that delivers messages, which is the read goroutine
+// unless WithDecodeWorkers or WithMessageBuffer are used. While it runs, no other message is delivered and,
+// without a message buffer, nothing is read from Twitter, so a slow handler can get the stream disconnected.
+// Messages handled by callbacks are never sent to the messages channel, but the channel is still closed when the
+// stream stops, so ranging over it is a convenient way to wait. Register callbacks before calling StartStream.
+func (s *Stream) OnMessage(handler func(StreamMessage)) {
+	s.onMessage = handler
+}
+
+// OnError registers a function that receives the Err of every message that has one, instead of the messages
+// channel or the OnMessage function. It is called the same way as OnMessage. Register it before calling StartStream.
+func (s *Stream) OnError(handler func(error)) {
+	s.onError = handler
+}
+
+// invokeCallbacks passes a message to the registered callbacks.
+// It returns false if no callback handles this message, so it should be sent to the messages channel.
+func (s *Stream) invokeCallbacks(message StreamMessage) bool {
+	switch {
+	case message.Err != nil && s.onError != nil:
+		s.onError(message.Err)
+	case s.onMessage != nil:
+		s.onMessage(message)
+	default:
+		return false
+	}
+	return true
+}
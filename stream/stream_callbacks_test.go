@@ -0,0 +1,43 @@
+package stream
+
+import (
+	"io"
+	"testing"
+)
+
+func TestOnMessageReplacesTheChannel(t *testing.T) {
+	instance := NewStream(givenNumberedStreamClient(3), NewStreamResponseBodyReader())
+
+	var received []StreamMessage
+	instance.OnMessage(func(message StreamMessage) {
+		received = append(received, message)
+	})
+
+	if messages := drain(t, instance); len(messages) != 0 {
+		t.Errorf("got %d messages on the channel, want 0", len(messages))
+	}
+
+	// three messages and the terminal io.EOF error
+	if len(received) != 4 || received[3].Err != io.EOF {
+		t.Errorf("got %v, want 3 messages and io.EOF", received)
+	}
+}
+
+func TestOnErrorReceivesErrors(t *testing.T) {
+	instance := NewStream(givenNumberedStreamClient(3), NewStreamResponseBodyReader())
+
+	var errs []error
+	instance.OnError(func(err error) {
+		errs = append(errs, err)
+	})
+
+	messages := drain(t, instance)
+
+	if len(messages) != 3 {
+		t.Errorf("got %d messages on the channel, want 3", len(messages))
+	}
+
+	if len(errs) != 1 || errs[0] != io.EOF {
+		t.Errorf("got %v, want io.EOF", errs)
+	}
+}
@@ -0,0 +1,67 @@
+package stream
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// StreamClosedError is the error that ended the stream when Twitter said why it closed the connection, either in
+// an operational message sent just before closing it, such as an `operational-disconnect`, or in the trailer of
+// the response. It is delivered as the last message and returned by Err. Use errors.As to get the reason, and
+// errors.Is with the read error it wraps, usually io.EOF. When Twitter gives no reason, the read error is not wrapped.
+type StreamClosedError struct {
+	Err error
+	// Warning is the last operational message of the connection, if any.
+	Warning *StreamWarning
+	// Trailer holds the trailer of the response, if Twitter sent one.
+	Trailer http.Header
+}
+
+// Error implements the error interface.
+func (e *StreamClosedError) Error() string {
+	return fmt.Sprintf("twitter closed the stream (%s): %v", e.Reason(), e.Err)
+}
+
+// Unwrap returns the read error.
+func (e *StreamClosedError) Unwrap() error {
+	return e.Err
+}
+
+// Reason returns the code and message of the last warning, or else the trailer, as one line.
+func (e *StreamClosedError) Reason() string {
+	if e.Warning != nil {
+		return e.Warning.Code + ": " + e.Warning.Message
+	}
+
+	var keys []string
+	for key := range e.Trailer {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var fields []string
+	for _, key := range keys {
+		fields = append(fields, key+": "+strings.Join(e.Trailer[key], ", "))
+	}
+	return strings.Join(fields, "; ")
+}
+
+// closeReason wraps the read error of the connection in a StreamClosedError if Twitter said why it was closed.
+func (s *Stream) closeReason(err error) error {
+	trailer := make(http.Header)
+	for key, values := range s.trailer {
+		if strings.Join(values, "") != "" {
+			trailer[key] = values
+		}
+	}
+
+	if s.lastWarning == nil && len(trailer) == 0 {
+		return err
+	}
+	if len(trailer) == 0 {
+		trailer = nil
+	}
+	return &StreamClosedError{Err: err, Warning: s.lastWarning, Trailer: trailer}
+}
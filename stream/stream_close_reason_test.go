@@ -0,0 +1,71 @@
+package stream
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"dev.freespoke.com/twitter-stream/httpclient"
+)
+
+func givenTrailerStreamClient(body string, trailer http.Header) httpclient.IHttpClient {
+	mockClient := httpclient.NewHttpClientMock("foobar")
+	mockClient.MockGetSearchStream = func(queryParams *url.Values) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(body)),
+			Trailer:    trailer,
+		}, nil
+	}
+	return mockClient
+}
+
+func TestStreamClosedError(t *testing.T) {
+	t.Run("an operational disconnect was sent before closing", func(t *testing.T) {
+		body := "{\"errors\":[{\"title\":\"operational-disconnect\",\"disconnect_type\":\"UpstreamOperationalDisconnect\"}]}\r\n"
+		instance := NewStream(givenStreamClient(body), NewStreamResponseBodyReader())
+
+		messages := drain(t, instance)
+
+		var closed *StreamClosedError
+		if !errors.As(instance.Err(), &closed) || !errors.Is(instance.Err(), io.EOF) {
+			t.Fatalf("got err %v, want a %T wrapping %v", instance.Err(), closed, io.EOF)
+		}
+		if closed.Reason() != "UpstreamOperationalDisconnect: operational-disconnect" {
+			t.Errorf("got reason %q", closed.Reason())
+		}
+		if last := messages[len(messages)-1].Err; last != instance.Err() {
+			t.Errorf("got last message err %v, want %v", last, instance.Err())
+		}
+	})
+
+	t.Run("a reason was sent in the trailer", func(t *testing.T) {
+		trailer := http.Header{"X-Close-Reason": {"maintenance"}, "X-Empty": {""}}
+		instance := NewStream(givenTrailerStreamClient("{\"data\":{\"id\":\"1\"}}\r\n", trailer), NewStreamResponseBodyReader())
+
+		drain(t, instance)
+
+		var closed *StreamClosedError
+		if !errors.As(instance.Err(), &closed) {
+			t.Fatalf("got err %v, want a %T", instance.Err(), closed)
+		}
+		if closed.Reason() != "X-Close-Reason: maintenance" {
+			t.Errorf("got reason %q, want the non-empty trailer", closed.Reason())
+		}
+	})
+
+	t.Run("no reason was sent", func(t *testing.T) {
+		instance := NewStream(givenTrailerStreamClient("{\"data\":{\"id\":\"1\"}}\r\n", http.Header{"X-Close-Reason": nil}),
+			NewStreamResponseBodyReader())
+
+		drain(t, instance)
+
+		if err := instance.Err(); err != io.EOF {
+			t.Errorf("got err %v, want %v", err, io.EOF)
+		}
+	})
+}
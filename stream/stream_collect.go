@@ -0,0 +1,37 @@
+package stream
+
+import (
+	"context"
+	"net/url"
+)
+
+// Collect starts the stream with `queryParams`, gathers up to `n` messages, then stops the stream and returns them.
+// It is meant for black-box tests and quick scripts that don't want to manage the messages channel.
+// Messages with an Err are collected too, and count toward `n`, so a StreamWarning takes the place of a tweet. Whatever was collected is returned when `ctx` is done, along with its error,
+// or when the stream ends early, along with the error that ended it. Don't use it with OnMessage, which takes
+// the messages Collect reads.
+func (s *Stream) Collect(ctx context.Context, queryParams *url.Values, n int) ([]StreamMessage, error) {
+	if err := s.StartStream(queryParams); err != nil {
+		return nil, err
+	}
+	defer func() {
+		s.StopStream()
+		s.waitFinished()
+	}()
+
+	messages := make([]StreamMessage, 0, n)
+	for len(messages) < n {
+		select {
+		case message, ok := <-s.messages:
+			if !ok {
+				<-s.finished
+				return messages, s.Err()
+			}
+			messages = append(messages, message)
+		case <-ctx.Done():
+			return messages, ctx.Err()
+		}
+	}
+
+	return messages, nil
+}
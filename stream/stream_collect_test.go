@@ -0,0 +1,86 @@
+package stream
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"dev.freespoke.com/twitter-stream/httpclient"
+)
+
+func TestCollect(t *testing.T) {
+	t.Run("stops after n messages", func(t *testing.T) {
+		instance := NewSyntheticStream(1000, func() []byte { return []byte(`{"data":{"id":"1"}}`) })
+
+		messages, err := instance.Collect(context.Background(), nil, 3)
+
+		if err != nil {
+			t.Errorf("got err %v", err)
+		}
+		if len(messages) != 3 {
+			t.Errorf("got %d messages, want 3", len(messages))
+		}
+		if _, ok := <-instance.GetMessages(); ok {
+			t.Errorf("expected the stream to be stopped")
+		}
+	})
+
+	t.Run("returns what was collected on timeout", func(t *testing.T) {
+		instance := NewSyntheticStream(100, func() []byte { return []byte(`{"data":{"id":"1"}}`) })
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		messages, err := instance.Collect(ctx, nil, 1000)
+
+		if err != context.DeadlineExceeded {
+			t.Errorf("got err %v, want %v", err, context.DeadlineExceeded)
+		}
+		if len(messages) == 0 || len(messages) >= 1000 {
+			t.Errorf("got %d messages, want some", len(messages))
+		}
+	})
+
+	t.Run("returns what was collected when the stream ends", func(t *testing.T) {
+		instance := NewStream(givenStreamClient("{\"data\":{\"id\":\"1\"}}\r\n"), NewStreamResponseBodyReader())
+
+		messages, err := instance.Collect(context.Background(), nil, 10)
+
+		if err != io.EOF {
+			t.Errorf("got err %v, want %v", err, io.EOF)
+		}
+		if len(messages) != 2 || messages[0].Err != nil || messages[1].Err != io.EOF {
+			t.Errorf("got %+v, want a message then the terminal error", messages)
+		}
+	})
+
+	t.Run("connects with the query params and counts warnings", func(t *testing.T) {
+		var query string
+		mockClient := httpclient.NewHttpClientMock("foobar")
+		mockClient.MockGetSearchStream = func(queryParams *url.Values) (*http.Response, error) {
+			query = queryParams.Encode()
+			body := "{\"errors\":[{\"title\":\"operational-disconnect\",\"disconnect_type\":\"reset\"}]}\r\n{\"data\":{\"id\":\"1\"}}\r\n"
+			return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(body))}, nil
+		}
+		instance := NewStream(mockClient, NewStreamResponseBodyReader())
+
+		messages, err := instance.Collect(context.Background(), NewStreamQueryParamsBuilder().AddTweetField("lang").Build(), 1)
+
+		if err != nil {
+			t.Errorf("got err %v", err)
+		}
+		if query != "tweet.fields=lang" {
+			t.Errorf("got query %q, want the query params", query)
+		}
+		if len(messages) != 1 {
+			t.Fatalf("got %+v, want only the warning", messages)
+		}
+		if _, ok := messages[0].Err.(*StreamWarning); !ok {
+			t.Errorf("got err %v, want the warning", messages[0].Err)
+		}
+	})
+}
@@ -0,0 +1,69 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrStoppedBeforeConnect is returned by WaitConnected when the stream was stopped before it connected.
+var ErrStoppedBeforeConnect = errors.New("stream stopped before connecting")
+
+// connectState records the outcome of the first connection attempt.
+type connectState struct {
+	mu      sync.Mutex
+	ready   chan struct{}
+	settled bool
+	err     error
+}
+
+// channel returns the channel that is closed once the first connection succeeded or permanently failed.
+func (c *connectState) channel() chan struct{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.ready == nil {
+		c.ready = make(chan struct{})
+	}
+	return c.ready
+}
+
+// settle records the outcome of the first connection. Later calls are ignored.
+func (c *connectState) settle(err error) {
+	ready := c.channel()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.settled {
+		return
+	}
+	c.settled = true
+	c.err = err
+	close(ready)
+}
+
+func (c *connectState) result() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+// WaitConnected blocks until the stream has connected to Twitter for the first time.
+// It can be called from any goroutine, before or after StartStream, which makes it a good readiness gate.
+// It returns the connection error if StartStream failed, ErrStoppedBeforeConnect if the stream was stopped first,
+// or the context's error if `ctx` is done before either happens.
+func (s *Stream) WaitConnected(ctx context.Context) error {
+	select {
+	case <-s.connected.channel():
+		return s.connected.result()
+	case <-s.done:
+		// the stream may have connected before it was stopped
+		select {
+		case <-s.connected.channel():
+			return s.connected.result()
+		default:
+			return ErrStoppedBeforeConnect
+		}
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,63 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"dev.freespoke.com/twitter-stream/httpclient"
+)
+
+func TestWaitConnected(t *testing.T) {
+	connectErr := errors.New("connect failed")
+	failingClient := httpclient.NewHttpClientMock("foobar")
+	failingClient.MockGetSearchStream = func(queryParams *url.Values) (*http.Response, error) {
+		return nil, connectErr
+	}
+
+	var tests = []struct {
+		name   string
+		client httpclient.IHttpClient
+		start  bool
+		want   error
+	}{
+		{"connected", givenStreamClient("1\r\n"), true, nil},
+		{"connect failed", failingClient, true, connectErr},
+		{"never started", givenStreamClient("1\r\n"), false, context.DeadlineExceeded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			instance := NewStream(tt.client, NewStreamResponseBodyReader())
+			defer instance.StopStream()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+
+			result := make(chan error, 1)
+			go func() {
+				result <- instance.WaitConnected(ctx)
+			}()
+
+			if tt.start {
+				_ = instance.StartStream(nil)
+			}
+
+			if err := <-result; !errors.Is(err, tt.want) {
+				t.Errorf("got %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestWaitConnectedAfterStop(t *testing.T) {
+	instance := NewStream(givenStreamClient(""), NewStreamResponseBodyReader())
+	instance.StopStream()
+
+	if err := instance.WaitConnected(context.Background()); err != ErrStoppedBeforeConnect {
+		t.Errorf("got %v, want %v", err, ErrStoppedBeforeConnect)
+	}
+}
@@ -0,0 +1,24 @@
+package stream
+
+import "encoding/json"
+
+// WithDataOnly passes only the `data` object of every message to the unmarshal hook, instead of the full envelope.
+// The `includes` and `matching_rules` of the message are discarded, so don't use it with expansions you need, or with
+// hooks that decode a tweet.StreamResponse. Messages without a `data` object are passed unchanged.
+// Defaults to false, which passes the full envelope.
+func WithDataOnly(dataOnly bool) Option {
+	return func(s *Stream) {
+		s.dataOnly = dataOnly
+	}
+}
+
+// unwrapData returns the `data` object of a message, or the message if it has none.
+func unwrapData(b []byte) []byte {
+	envelope := struct {
+		Data json.RawMessage `json:"data"`
+	}{}
+	if err := json.Unmarshal(b, &envelope); err != nil || len(envelope.Data) == 0 {
+		return b
+	}
+	return envelope.Data
+}
@@ -0,0 +1,22 @@
+package stream
+
+import "testing"
+
+func TestDataOnly(t *testing.T) {
+	body := "{\"data\":{\"id\":\"1\"},\"includes\":{\"users\":[]},\"matching_rules\":[{\"tag\":\"cats\"}]}\r\n{\"other\":true}\r\n"
+
+	instance := NewStream(givenStreamClient(body), NewStreamResponseBodyReader(), WithDataOnly(true))
+	instance.SetUnmarshalHook(func(b []byte) (interface{}, error) {
+		return string(b), nil
+	})
+
+	messages := drain(t, instance)
+
+	if messages[0].Data != "{\"id\":\"1\"}" {
+		t.Errorf("got %q, want only the data object", messages[0].Data)
+	}
+
+	if messages[1].Data != "{\"other\":true}" {
+		t.Errorf("got %q, want messages without data unchanged", messages[1].Data)
+	}
+}
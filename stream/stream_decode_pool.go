@@ -0,0 +1,180 @@
+package stream
+
+import (
+	"sync"
+	"time"
+)
+
+// decodePool runs the unmarshal hook for messages read from the stream.
+// Without workers, messages are decoded and delivered on the read goroutine.
+// With workers, the read goroutine copies each message and hands it to `decodeWorkers` goroutines, while a single
+// goroutine delivers the results either in the order they were read or in the order they were decoded.
+type decodePool struct {
+	stream    *Stream
+	hook      UnmarshalHook
+	jobs      chan decodeJob
+	ordered   chan chan decodedMessage
+	unordered chan decodedMessage
+	workers   sync.WaitGroup
+	delivered chan struct{}
+}
+
+type decodeJob struct {
+	buffer     *[]byte
+	sequence   uint64
+	receivedAt time.Time
+	result     chan decodedMessage
+}
+
+// decodedMessage is a message ready to be delivered, along with the pooled buffer its bytes were copied to, if any.
+// Size is the number of bytes the message was read from, counted against WithMaxBufferedBytes.
+type decodedMessage struct {
+	message StreamMessage
+	buffer  *[]byte
+	size    int
+}
+
+// WithDecodeWorkers runs the unmarshal hook on a pool of `n` goroutines instead of the read goroutine,
+// so expensive decoding doesn't slow down reading and get the stream disconnected by Twitter.
+// Messages are still delivered in the order they were read, unless WithUnorderedDecode is also used.
+// Because decoding happens off the read goroutine, every message is copied before it is handed to the hook.
+// The unmarshal hook must be safe to call from several goroutines at once.
+func WithDecodeWorkers(n int) Option {
+	return func(s *Stream) {
+		s.decodeWorkers = n
+	}
+}
+
+// WithUnorderedDecode delivers messages decoded by WithDecodeWorkers as soon as they are decoded.
+// A slow message no longer holds up the ones read after it, but messages may arrive out of order.
+// The StreamMessage Sequence is assigned when a message is read, so it can be used to restore the order.
+func WithUnorderedDecode() Option {
+	return func(s *Stream) {
+		s.unorderedDecode = true
+	}
+}
+
+func newDecodePool(s *Stream) *decodePool {
+	p := &decodePool{stream: s, hook: s.unmarshalHook}
+	if s.decodeWorkers <= 0 {
+		return p
+	}
+
+	p.jobs = make(chan decodeJob, s.decodeWorkers)
+	p.delivered = make(chan struct{})
+	if s.unorderedDecode {
+		p.unordered = make(chan decodedMessage, s.decodeWorkers)
+		go p.deliverUnordered()
+	} else {
+		p.ordered = make(chan chan decodedMessage, s.decodeWorkers)
+		go p.deliverOrdered()
+	}
+
+	p.workers.Add(s.decodeWorkers)
+	for i := 0; i < s.decodeWorkers; i++ {
+		go p.work()
+	}
+
+	return p
+}
+
+// decode runs the unmarshal hook for a message and delivers the result stamped with `sequence`.
+func (p *decodePool) decode(b []byte, sequence uint64, receivedAt time.Time) {
+	if p.jobs == nil {
+		buffer := p.stream.copyToBuffer(b)
+		if buffer != nil {
+			b = *buffer
+		}
+
+		p.stream.deliverBuffered(decodedMessage{message: p.run(b, sequence, receivedAt), buffer: buffer, size: len(b)})
+		return
+	}
+
+	buffer := p.stream.copyToBuffer(b)
+	if buffer == nil {
+		copied := append([]byte(nil), b...)
+		buffer = &copied
+	}
+
+	job := decodeJob{buffer: buffer, sequence: sequence, receivedAt: receivedAt}
+	if p.ordered != nil {
+		job.result = make(chan decodedMessage, 1)
+		p.ordered <- job.result
+	}
+	p.jobs <- job
+}
+
+// deliver delivers a message that doesn't need decoding, keeping its place in the order.
+func (p *decodePool) deliver(message StreamMessage) {
+	switch {
+	case p.ordered != nil:
+		result := make(chan decodedMessage, 1)
+		result <- decodedMessage{message: message}
+		p.ordered <- result
+	case p.unordered != nil:
+		p.unordered <- decodedMessage{message: message}
+	default:
+		p.stream.deliver(message)
+	}
+}
+
+// close waits until every message handed to the pool has been delivered.
+func (p *decodePool) close() {
+	if p.jobs == nil {
+		return
+	}
+
+	close(p.jobs)
+	if p.ordered != nil {
+		close(p.ordered)
+	}
+	p.workers.Wait()
+	if p.unordered != nil {
+		close(p.unordered)
+	}
+	<-p.delivered
+}
+
+func (p *decodePool) run(b []byte, sequence uint64, receivedAt time.Time) StreamMessage {
+	data, err := p.hook(b)
+	if err != nil {
+		p.stream.stats.addDecodeError()
+	}
+	return StreamMessage{
+		Data:       data,
+		Err:        err,
+		Sequence:   sequence,
+		ReceivedAt: receivedAt,
+	}
+}
+
+func (p *decodePool) work() {
+	defer p.workers.Done()
+	for job := range p.jobs {
+		decoded := decodedMessage{message: p.run(*job.buffer, job.sequence, job.receivedAt), size: len(*job.buffer)}
+		if p.stream.bufferPool != nil {
+			decoded.buffer = job.buffer
+		}
+
+		if job.result != nil {
+			job.result <- decoded
+		} else {
+			p.unordered <- decoded
+		}
+	}
+}
+
+func (p *decodePool) deliverOrdered() {
+	defer close(p.delivered)
+	for result := range p.ordered {
+		decoded := <-result
+		p.stream.deliverBuffered(decoded)
+	}
+}
+
+func (p *decodePool) deliverUnordered() {
+	defer close(p.delivered)
+	for decoded := range p.unordered {
+		p.stream.deliverBuffered(decoded)
+	}
+}
@@ -0,0 +1,106 @@
+package stream
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+
+	"dev.freespoke.com/twitter-stream/httpclient"
+)
+
+func givenNumberedStreamClient(count int) httpclient.IHttpClient {
+	var body bytes.Buffer
+	for i := 1; i <= count; i++ {
+		body.WriteString(fmt.Sprintf("%d\r\n", i))
+	}
+
+	mockClient := httpclient.NewHttpClientMock("foobar")
+	mockClient.MockGetSearchStream = func(queryParams *url.Values) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(bytes.NewReader(body.Bytes())),
+		}, nil
+	}
+	return mockClient
+}
+
+func givenSlowNumberHook(b []byte) (interface{}, error) {
+	n, err := strconv.Atoi(string(b))
+	// decode earlier messages slower so an unordered pool would reorder them
+	time.Sleep(time.Duration(20-n%20) * 100 * time.Microsecond)
+	return n, err
+}
+
+func collectNumbers(t *testing.T, instance IStream) []int {
+	if err := instance.StartStream(nil); err != nil {
+		t.Fatalf("got err when starting stream %v", err)
+	}
+
+	var result []int
+	for message := range instance.GetMessages() {
+		if message.Err != nil {
+			continue
+		}
+		result = append(result, message.Data.(int))
+	}
+	return result
+}
+
+func TestDecodeWorkersPreserveOrder(t *testing.T) {
+	instance := NewStream(givenNumberedStreamClient(100), NewStreamResponseBodyReader(), WithDecodeWorkers(4))
+	instance.SetUnmarshalHook(givenSlowNumberHook)
+
+	result := collectNumbers(t, instance)
+
+	if len(result) != 100 {
+		t.Fatalf("got %d messages, want 100", len(result))
+	}
+
+	for i, n := range result {
+		if n != i+1 {
+			t.Fatalf("got %d at position %d, want %d", n, i, i+1)
+		}
+	}
+}
+
+func TestUnorderedDecodeWorkersDeliverEveryMessage(t *testing.T) {
+	instance := NewStream(givenNumberedStreamClient(100), NewStreamResponseBodyReader(), WithDecodeWorkers(4), WithUnorderedDecode())
+	instance.SetUnmarshalHook(givenSlowNumberHook)
+
+	result := collectNumbers(t, instance)
+	sort.Ints(result)
+
+	if len(result) != 100 {
+		t.Fatalf("got %d messages, want 100", len(result))
+	}
+
+	for i, n := range result {
+		if n != i+1 {
+			t.Fatalf("got %d at position %d, want %d", n, i, i+1)
+		}
+	}
+}
+
+func TestUnorderedDecodeKeepsTheReadSequence(t *testing.T) {
+	instance := NewStream(givenNumberedStreamClient(100), NewStreamResponseBodyReader(), WithDecodeWorkers(4), WithUnorderedDecode())
+	instance.SetUnmarshalHook(givenSlowNumberHook)
+
+	for _, message := range drain(t, instance) {
+		if message.Err != nil {
+			if message.Sequence != 101 {
+				t.Errorf("got sequence %d for %v, want 101", message.Sequence, message.Err)
+			}
+			continue
+		}
+
+		if n := message.Data.(int); message.Sequence != uint64(n) {
+			t.Errorf("got sequence %d for message %d", message.Sequence, n)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package stream
+
+import (
+	"container/list"
+	"encoding/json"
+)
+
+// WithDedup skips tweets whose "data.id" was one of the last `windowSize` ids read, for example tweets that
+// are sent again after a reconnect because backfill_minutes was requested. Skipped tweets are counted in
+// Stats().Duplicates and never reach the unmarshal hook or take a sequence number.
+// Finding the id decodes every message on the read goroutine, so it is opt-in.
+func WithDedup(windowSize int) Option {
+	return func(s *Stream) {
+		if windowSize > 0 {
+			s.dedup = newIDWindow(windowSize)
+		}
+	}
+}
+
+// idWindow remembers the most recently seen ids, evicting the least recently seen once it is full.
+// It is only used by the read goroutine.
+type idWindow struct {
+	size    int
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+func newIDWindow(size int) *idWindow {
+	return &idWindow{size: size, order: list.New(), entries: make(map[string]*list.Element, size)}
+}
+
+// seen records `id` and returns true if it was already in the window.
+func (w *idWindow) seen(id string) bool {
+	if element, ok := w.entries[id]; ok {
+		w.order.MoveToFront(element)
+		return true
+	}
+
+	w.entries[id] = w.order.PushFront(id)
+	if w.order.Len() > w.size {
+		oldest := w.order.Back()
+		w.order.Remove(oldest)
+		delete(w.entries, oldest.Value.(string))
+	}
+	return false
+}
+
+// isDuplicate returns true if the message is a tweet that was already read.
+// Messages without an id are never duplicates.
+func (s *Stream) isDuplicate(b []byte) bool {
+	message := struct {
+		Data struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}{}
+	if err := json.Unmarshal(b, &message); err != nil || message.Data.ID == "" {
+		return false
+	}
+
+	if s.dedup.seen(message.Data.ID) {
+		s.stats.addDuplicate()
+		return true
+	}
+	return false
+}
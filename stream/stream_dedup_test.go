@@ -0,0 +1,43 @@
+package stream
+
+import "testing"
+
+func TestDedupSkipsRepeatedTweets(t *testing.T) {
+	body := "{\"data\":{\"id\":\"1\"}}\r\n{\"data\":{\"id\":\"2\"}}\r\n{\"data\":{\"id\":\"1\"}}\r\n" +
+		"{\"data\":{\"id\":\"3\"}}\r\n{\"data\":{\"id\":\"4\"}}\r\n{\"data\":{\"id\":\"1\"}}\r\n"
+
+	instance := NewStream(givenStreamClient(body), NewStreamResponseBodyReader(), WithDedup(2))
+	instance.SetUnmarshalHook(func(b []byte) (interface{}, error) {
+		return string(b), nil
+	})
+
+	messages := drain(t, instance)
+
+	// 1, 2, 3, 4, then 1 again once it was evicted, and io.EOF
+	if len(messages) != 6 {
+		t.Errorf("got %d messages, want 6", len(messages))
+	}
+
+	if instance.Stats().Duplicates != 1 {
+		t.Errorf("got %d duplicates, want 1", instance.Stats().Duplicates)
+	}
+
+	for i, message := range messages {
+		if message.Sequence != uint64(i+1) {
+			t.Errorf("got sequence %d at %d, want no gaps", message.Sequence, i)
+		}
+	}
+}
+
+func TestIDWindowEvictsLeastRecentlySeen(t *testing.T) {
+	window := newIDWindow(2)
+
+	for _, id := range []string{"1", "2", "1", "3"} {
+		window.seen(id)
+	}
+
+	// 2 was evicted because 1 was seen again after it
+	if window.seen("1") != true || window.seen("2") != false {
+		t.Errorf("expected 1 to be kept and 2 to be evicted")
+	}
+}
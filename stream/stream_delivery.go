@@ -0,0 +1,232 @@
+package stream
+
+import "time"
+
+// OverflowPolicy decides what happens to a message when the message buffer is full.
+type OverflowPolicy int
+
+const (
+	// OverflowBlock waits for room in the message buffer, which stops reading from Twitter until the consumer
+	// catches up. Twitter disconnects streams that fall too far behind. This is the default.
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDropNewest discards the message that doesn't fit so reading never stops.
+	// Dropped messages are counted in Stats().Dropped. Errors are never dropped.
+	OverflowDropNewest
+)
+
+// outbox is a buffer between reading messages and delivering them to the messages channel.
+// It lets the stream keep reading while the consumer is slow, or while delivery is throttled by WithMaxMessageRate.
+type outbox struct {
+	stream    *Stream
+	queue     chan decodedMessage
+	bucket    *tokenBucket
+	delivered chan struct{}
+	// freed is signaled whenever a message leaves the buffer, to wake a push waiting for WithMaxBufferedBytes.
+	freed chan struct{}
+}
+
+// Without WithMessageBuffer, WithMaxBufferedBytes buffers at most one message per minBufferedMessageSize bytes,
+// or minBufferedMessages messages, whichever is more.
+const (
+	minBufferedMessageSize = 128
+	minBufferedMessages    = 64
+)
+
+// tokenBucket allows one message every interval, with a burst of one.
+type tokenBucket struct {
+	interval time.Duration
+	next     time.Time
+}
+
+// WithMessageBuffer buffers up to `n` messages that have been read but not yet received by the consumer.
+// What happens when the buffer is full is decided by WithOverflowPolicy.
+// Defaults to 0, which hands every message straight to the consumer.
+func WithMessageBuffer(n int) Option {
+	return func(s *Stream) {
+		s.messageBuffer = n
+	}
+}
+
+// WithOverflowPolicy sets what happens to messages that don't fit in the message buffer. Defaults to OverflowBlock.
+func WithOverflowPolicy(policy OverflowPolicy) Option {
+	return func(s *Stream) {
+		s.overflowPolicy = policy
+	}
+}
+
+// WithMaxBufferedBytes caps the total size of the messages that have been read but not yet received by the
+// consumer at `n` bytes, counting the bytes each message was read from, so memory is bounded even when message
+// sizes vary wildly. When a message doesn't fit, the overflow policy of WithOverflowPolicy applies as if the
+// buffer was full: OverflowBlock stops reading until enough bytes are delivered, OverflowDropNewest discards it.
+// A message larger than `n` is only buffered once the buffer is empty. The bytes currently buffered are in
+// Stats().BufferedBytes. It can be combined with WithMessageBuffer, and both limits apply; without it, the buffer
+// also holds at most 64 messages or one message per 128 bytes of `n`, whichever is more.
+func WithMaxBufferedBytes(n int) Option {
+	return func(s *Stream) {
+		s.maxBufferedBytes = n
+	}
+}
+
+// WithMaxMessageRate throttles delivery to at most `perSecond` messages per second using a token bucket.
+// This is meant for development, to avoid overwhelming a downstream test system.
+//
+// The stream keeps reading from Twitter while delivery is throttled, so messages pile up in the message buffer,
+// which defaults to `perSecond` messages unless WithMessageBuffer is used. Once the buffer is full, the overflow
+// policy applies: OverflowBlock stops reading, and Twitter will eventually disconnect a stream that can't keep up,
+// while OverflowDropNewest discards messages to keep the connection healthy.
+func WithMaxMessageRate(perSecond int) Option {
+	return func(s *Stream) {
+		s.maxMessageRate = perSecond
+	}
+}
+
+// newOutbox starts delivering messages from a buffer, if the stream is configured to use one.
+func newOutbox(s *Stream) *outbox {
+	size := s.messageBuffer
+	if size <= 0 && s.maxBufferedBytes > 0 {
+		size = s.maxBufferedBytes / minBufferedMessageSize
+		if size < minBufferedMessages {
+			size = minBufferedMessages
+		}
+	}
+	if size <= 0 && s.maxMessageRate > 0 {
+		size = s.maxMessageRate
+	}
+	if size <= 0 {
+		return nil
+	}
+
+	o := &outbox{
+		stream:    s,
+		queue:     make(chan decodedMessage, size),
+		delivered: make(chan struct{}),
+		freed:     make(chan struct{}, 1),
+	}
+	if s.maxMessageRate > 0 {
+		o.bucket = &tokenBucket{interval: time.Second / time.Duration(s.maxMessageRate)}
+	}
+
+	s.outbox = o
+	go o.run()
+	return o
+}
+
+// close waits until every buffered message has been delivered.
+func (o *outbox) close() {
+	if o == nil {
+		return
+	}
+	close(o.queue)
+	<-o.delivered
+}
+
+// push adds a message to the buffer, applying the overflow policy if it is full.
+func (o *outbox) push(decoded decodedMessage) {
+	if o.stream.overflowPolicy == OverflowDropNewest && decoded.message.Err == nil {
+		if !o.stream.stats.reserveBuffered(decoded.size, o.stream.maxBufferedBytes) {
+			o.stream.stats.addDropped()
+			o.stream.releaseBuffer(decoded.buffer)
+			return
+		}
+		select {
+		case o.queue <- decoded:
+		default:
+			o.stream.stats.releaseBuffered(decoded.size)
+			o.stream.stats.addDropped()
+			o.stream.releaseBuffer(decoded.buffer)
+		}
+		return
+	}
+
+	for !o.stream.stats.reserveBuffered(decoded.size, o.stream.maxBufferedBytes) {
+		select {
+		case <-o.freed:
+		case <-o.stream.done:
+			o.stream.stats.addDiscarded()
+			o.stream.releaseBuffer(decoded.buffer)
+			return
+		}
+	}
+
+	select {
+	case o.queue <- decoded:
+	case <-o.stream.done:
+		o.stream.stats.releaseBuffered(decoded.size)
+		o.stream.stats.addDiscarded()
+		o.stream.releaseBuffer(decoded.buffer)
+	}
+}
+
+func (o *outbox) run() {
+	defer close(o.delivered)
+	for decoded := range o.queue {
+		if o.bucket != nil {
+			o.bucket.wait(o.stream.done)
+		}
+		o.stream.send(decoded)
+		o.stream.stats.releaseBuffered(decoded.size)
+		select {
+		case o.freed <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// wait blocks until the next message may be delivered, or done is closed.
+func (b *tokenBucket) wait(done <-chan struct{}) {
+	now := time.Now()
+	if b.next.Before(now) {
+		b.next = now
+	}
+
+	if delay := b.next.Sub(now); delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-done:
+		}
+	}
+	b.next = b.next.Add(b.interval)
+}
+
+// deliver sends a message, already stamped with its sequence number, to the messages channel.
+// The message is discarded if the stream is stopped while waiting for the consumer.
+func (s *Stream) deliver(message StreamMessage) {
+	s.deliverBuffered(decodedMessage{message: message})
+}
+
+// deliverBuffered delivers a message whose bytes may live in a pooled buffer, through the message buffer if there is one.
+func (s *Stream) deliverBuffered(decoded decodedMessage) {
+	if s.outbox != nil {
+		s.outbox.push(decoded)
+		return
+	}
+	s.send(decoded)
+}
+
+// send hands a message to the consumer.
+// Once the consumer receives this message, it is done with the previous one, so the previous buffer is released.
+func (s *Stream) send(decoded decodedMessage) {
+	if stopped(s.done) {
+		s.stats.addDiscarded()
+		s.releaseBuffer(decoded.buffer)
+		return
+	}
+
+	if s.invokeCallbacks(decoded.message) {
+		s.stats.addDelivered()
+		s.releaseBuffer(decoded.buffer)
+		return
+	}
+
+	select {
+	case s.messages <- decoded.message:
+		s.stats.addDelivered()
+		s.releaseBuffer(s.pendingBuffer)
+		s.pendingBuffer = decoded.buffer
+	case <-s.done:
+		s.stats.addDiscarded()
+		s.releaseBuffer(decoded.buffer)
+	}
+}
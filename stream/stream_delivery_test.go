@@ -0,0 +1,162 @@
+package stream
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMaxMessageRateThrottlesDelivery(t *testing.T) {
+	instance := NewStream(givenNumberedStreamClient(5), NewStreamResponseBodyReader(), WithMaxMessageRate(100))
+
+	start := time.Now()
+	messages := drain(t, instance)
+	elapsed := time.Since(start)
+
+	// five messages and the terminal io.EOF error
+	if len(messages) != 6 {
+		t.Fatalf("got %d messages, want 6", len(messages))
+	}
+
+	if elapsed < 40*time.Millisecond {
+		t.Errorf("got %v, want at least 40ms for 6 messages at 100 per second", elapsed)
+	}
+}
+
+func TestOverflowDropNewestKeepsReading(t *testing.T) {
+	instance := NewStream(givenNumberedStreamClient(10), NewStreamResponseBodyReader(),
+		WithMessageBuffer(2),
+		WithOverflowPolicy(OverflowDropNewest),
+	)
+
+	if err := instance.StartStream(nil); err != nil {
+		t.Fatalf("got err when starting stream %v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	tweets, errs := 0, 0
+	for message := range instance.GetMessages() {
+		if message.Err != nil {
+			errs++
+		} else {
+			tweets++
+		}
+	}
+
+	dropped := instance.Stats().Dropped
+	if dropped == 0 {
+		t.Errorf("expected messages to be dropped")
+	}
+
+	if uint64(tweets)+dropped != 10 {
+		t.Errorf("got %d delivered and %d dropped, want 10 in total", tweets, dropped)
+	}
+
+	if errs != 1 {
+		t.Errorf("got %d errors, want the terminal error to never be dropped", errs)
+	}
+}
+
+func TestDroppedMessagesLeaveSequenceGaps(t *testing.T) {
+	instance := NewStream(givenNumberedStreamClient(10), NewStreamResponseBodyReader(),
+		WithMessageBuffer(2),
+		WithOverflowPolicy(OverflowDropNewest),
+	)
+
+	if err := instance.StartStream(nil); err != nil {
+		t.Fatalf("got err when starting stream %v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	var last, gaps uint64
+	for message := range instance.GetMessages() {
+		gaps += message.Sequence - last - 1
+		last = message.Sequence
+	}
+
+	if dropped := instance.Stats().Dropped; dropped == 0 || gaps != dropped {
+		t.Errorf("got %d missing sequence numbers and %d dropped, want them equal and non-zero", gaps, dropped)
+	}
+}
+
+func TestMessageBufferBlocksByDefault(t *testing.T) {
+	instance := NewStream(givenNumberedStreamClient(10), NewStreamResponseBodyReader(), WithMessageBuffer(2))
+
+	messages := drain(t, instance)
+
+	if len(messages) != 11 {
+		t.Errorf("got %d messages, want 11", len(messages))
+	}
+
+	if instance.Stats().Dropped != 0 {
+		t.Errorf("got %d dropped, want 0", instance.Stats().Dropped)
+	}
+}
+
+func TestMaxBufferedBytes(t *testing.T) {
+	// ten messages of 10 bytes, of which at most two fit in 25 bytes
+	body := strings.Repeat("0123456789\r\n", 10)
+
+	t.Run("drops what doesn't fit", func(t *testing.T) {
+		instance := NewStream(givenStreamClient(body), NewStreamResponseBodyReader(),
+			WithMaxBufferedBytes(25),
+			WithOverflowPolicy(OverflowDropNewest),
+		)
+		if err := instance.StartStream(nil); err != nil {
+			t.Fatalf("got err when starting stream %v", err)
+		}
+		time.Sleep(50 * time.Millisecond)
+
+		if got := instance.Stats().BufferedBytes; got != 20 {
+			t.Errorf("got %d buffered bytes, want 20", got)
+		}
+
+		tweets := 0
+		for message := range instance.GetMessages() {
+			if message.Err == nil {
+				tweets++
+			}
+		}
+
+		stats := instance.Stats()
+		if tweets != 2 || stats.Dropped != 8 {
+			t.Errorf("got %d delivered and %d dropped, want 2 and 8", tweets, stats.Dropped)
+		}
+		if stats.BufferedBytes != 0 {
+			t.Errorf("got %d buffered bytes once drained, want 0", stats.BufferedBytes)
+		}
+	})
+
+	t.Run("blocks until bytes are delivered", func(t *testing.T) {
+		instance := NewStream(givenStreamClient(body), NewStreamResponseBodyReader(), WithMaxBufferedBytes(25))
+		if err := instance.StartStream(nil); err != nil {
+			t.Fatalf("got err when starting stream %v", err)
+		}
+		time.Sleep(50 * time.Millisecond)
+
+		if got := instance.Stats().BufferedBytes; got != 20 {
+			t.Errorf("got %d buffered bytes, want 20", got)
+		}
+
+		messages := 0
+		for range instance.GetMessages() {
+			messages++
+		}
+		if messages != 11 || instance.Stats().Dropped != 0 {
+			t.Errorf("got %d messages and %d dropped, want 11 and 0", messages, instance.Stats().Dropped)
+		}
+	})
+
+	t.Run("a message larger than the cap still goes through", func(t *testing.T) {
+		instance := NewStream(givenStreamClient(body), NewStreamResponseBodyReader(),
+			WithMaxBufferedBytes(5),
+			WithOverflowPolicy(OverflowDropNewest),
+		)
+
+		messages := drain(t, instance)
+
+		if len(messages) < 2 || messages[0].Err != nil {
+			t.Errorf("got %+v, want the first message delivered", messages)
+		}
+	})
+}
@@ -0,0 +1,61 @@
+package stream
+
+import "time"
+
+// Disconnect reasons Twitter sends as the `disconnect_type` of an `errors` message before it closes the stream.
+// They are the Code of the StreamWarning delivered for that message.
+const (
+	DisconnectOperational         = "OperationalDisconnect"
+	DisconnectUpstreamOperational = "UpstreamOperationalDisconnect"
+	DisconnectForce               = "ForceDisconnect"
+	DisconnectUpstreamUnclean     = "UpstreamUncleanDisconnect"
+	DisconnectSlowReader          = "SlowReader"
+	DisconnectInternalError       = "InternalError"
+)
+
+// slowReaderBackoffFactor is how much longer the stream waits to reconnect after being disconnected for reading too slowly.
+const slowReaderBackoffFactor = 4
+
+// ReasonBackoff decides how long to wait before reconnect `attempt`, starting at 0, after Twitter disconnected the
+// stream with `reason`. `delay` is what the Backoff of the stream would wait. `reason` is the code of the last
+// StreamWarning received on the connection, or empty if it dropped without one.
+type ReasonBackoff func(reason string, attempt int, delay time.Duration) time.Duration
+
+// WithReasonBackoff replaces how the reconnect delay is adjusted for the reason Twitter gave for disconnecting.
+// By default, DefaultReasonBackoff is used.
+func WithReasonBackoff(backoff ReasonBackoff) Option {
+	return func(s *Stream) {
+		s.reasonBackoff = backoff
+	}
+}
+
+// DefaultReasonBackoff follows Twitter's guidance per disconnect reason: after maintenance, an operational
+// disconnect, the first reconnect is made immediately. After a SlowReader disconnect, the stream backs off
+// longer, since reconnecting straight away will fall behind again. Every other reason uses `delay`.
+func DefaultReasonBackoff(reason string, attempt int, delay time.Duration) time.Duration {
+	switch reason {
+	case DisconnectOperational, DisconnectUpstreamOperational:
+		if attempt == 0 {
+			return 0
+		}
+	case DisconnectSlowReader:
+		delay *= slowReaderBackoffFactor
+		if delay > maxBackoff {
+			delay = maxBackoff
+		}
+	}
+	return delay
+}
+
+// reconnectDelay returns how long to wait before reconnect `attempt`, for the reason of the last disconnect.
+func (s *Stream) reconnectDelay(attempt int) time.Duration {
+	backoff := s.reasonBackoff
+	if backoff == nil {
+		backoff = DefaultReasonBackoff
+	}
+	var reason string
+	if s.lastWarning != nil {
+		reason = s.lastWarning.Code
+	}
+	return backoff(reason, attempt, s.backoff.NextDelay(attempt))
+}
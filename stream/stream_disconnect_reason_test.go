@@ -0,0 +1,69 @@
+package stream
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"dev.freespoke.com/twitter-stream/httpclient"
+)
+
+func TestDefaultReasonBackoff(t *testing.T) {
+	var tests = []struct {
+		reason  string
+		attempt int
+		delay   time.Duration
+		want    time.Duration
+	}{
+		{DisconnectUpstreamOperational, 0, time.Second, 0},
+		{DisconnectOperational, 0, time.Second, 0},
+		{DisconnectOperational, 1, 2 * time.Second, 2 * time.Second},
+		{DisconnectSlowReader, 0, time.Second, 4 * time.Second},
+		{DisconnectSlowReader, 4, 16 * time.Second, maxBackoff},
+		{DisconnectForce, 0, time.Second, time.Second},
+		{"", 0, time.Second, time.Second},
+	}
+
+	for i, tt := range tests {
+		testName := fmt.Sprintf("TestDefaultReasonBackoff (%d)", i)
+
+		t.Run(testName, func(t *testing.T) {
+			if got := DefaultReasonBackoff(tt.reason, tt.attempt, tt.delay); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReconnectUsesTheDisconnectReason(t *testing.T) {
+	bodies := []string{
+		"{\"errors\":[{\"title\":\"operational-disconnect\",\"disconnect_type\":\"UpstreamOperationalDisconnect\"}]}\r\n",
+		"1\r\n",
+	}
+	mockClient := httpclient.NewHttpClientMock("foobar")
+	mockClient.MockGetSearchStream = func(queryParams *url.Values) (*http.Response, error) {
+		if len(bodies) == 0 {
+			return nil, httpclient.ErrConnectionLimit
+		}
+		body := bodies[0]
+		bodies = bodies[1:]
+		return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(bytes.NewReader([]byte(body)))}, nil
+	}
+
+	var reasons []string
+	instance := NewStream(mockClient, NewStreamResponseBodyReader(), WithAutoReconnect(),
+		WithReasonBackoff(func(reason string, attempt int, delay time.Duration) time.Duration {
+			reasons = append(reasons, reason)
+			return 0
+		}))
+
+	drain(t, instance)
+
+	if fmt.Sprint(reasons) != fmt.Sprint([]string{DisconnectUpstreamOperational, ""}) {
+		t.Errorf("got reasons %q, want the upstream disconnect then none", reasons)
+	}
+}
@@ -0,0 +1,89 @@
+package stream
+
+import (
+	"context"
+	"net/url"
+	"time"
+)
+
+// Err returns why the messages channel closed: nil when the stream was stopped with StopStream,
+// context.Canceled (or the context's other error) when the context of StartStreamContext was done,
+// or the error that ended the stream, which is also the last message sent. That error is a StreamClosedError
+// when Twitter said why it closed the connection. Like bufio.Scanner.Err, check it after ranging over GetMessages.
+func (s *Stream) Err() error {
+	s.errMu.Lock()
+	defer s.errMu.Unlock()
+	if s.terminalErr != nil {
+		return s.terminalErr
+	}
+	return s.canceled
+}
+
+// WithClosedMessage sends a last message with Closed set when the stream ends, so the end of the stream can
+// be handled inside the range loop over GetMessages. The channel closes right after it. Its Err is the error Err
+// returns: the error that ended the stream, nil when StopStream was called, or the context's error.
+// It is sent even after StopStream, once the messages before it were discarded, so keep ranging until the channel
+// closes. StopWithReport and Collect don't wait for it to be read, as they may be called by the consumer itself.
+func WithClosedMessage() Option {
+	return func(s *Stream) {
+		s.closedMessage = true
+	}
+}
+
+// sendClosed hands the closed message to the consumer, even when the stream was stopped.
+// It only gives up when waitFinished was called, since the caller may be the consumer.
+func (s *Stream) sendClosed() {
+	message := StreamMessage{Err: s.Err(), Sequence: s.nextSequence(), ReceivedAt: time.Now(), Closed: true}
+	if s.invokeCallbacks(message) {
+		s.stats.addDelivered()
+		return
+	}
+
+	select {
+	case s.messages <- message:
+		s.stats.addDelivered()
+		s.releaseBuffer(s.pendingBuffer)
+		s.pendingBuffer = nil
+	case <-s.abandoned:
+	}
+}
+
+// waitFinished waits for the stream to finish, without waiting for the consumer to read the closed message.
+func (s *Stream) waitFinished() {
+	s.abandonOnce.Do(func() {
+		close(s.abandoned)
+	})
+	<-s.finished
+}
+
+// StartStreamContext starts the stream like StartStream, and stops it when `ctx` is done.
+// Err then returns the context's error.
+func (s *Stream) StartStreamContext(ctx context.Context, queryParams *url.Values) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := s.StartStream(queryParams); err != nil {
+		return err
+	}
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			s.errMu.Lock()
+			if !stopped(s.done) {
+				s.canceled = ctx.Err()
+			}
+			s.errMu.Unlock()
+			s.StopStream()
+		case <-s.finished:
+		}
+	}()
+
+	return nil
+}
+
+func (s *Stream) setTerminalErr(err error) {
+	s.errMu.Lock()
+	defer s.errMu.Unlock()
+	s.terminalErr = err
+}
@@ -0,0 +1,126 @@
+package stream
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestErr(t *testing.T) {
+	t.Run("the stream ended with an error", func(t *testing.T) {
+		instance := NewStream(givenStreamClient("{\"data\":{\"id\":\"1\"}}\r\n"), NewStreamResponseBodyReader())
+
+		drain(t, instance)
+
+		if err := instance.Err(); err != io.EOF {
+			t.Errorf("got err %v, want %v", err, io.EOF)
+		}
+	})
+
+	t.Run("the stream was stopped", func(t *testing.T) {
+		instance := NewStream(givenSilentStreamClient("{\"data\":{\"id\":\"1\"}}\r\n"), NewStreamResponseBodyReader())
+		if err := instance.StartStream(nil); err != nil {
+			t.Fatalf("got err when starting stream %v", err)
+		}
+
+		<-instance.GetMessages()
+		instance.StopStream()
+		for range instance.GetMessages() {
+		}
+
+		if err := instance.Err(); err != nil {
+			t.Errorf("got err %v, want nil", err)
+		}
+	})
+
+	t.Run("the context was canceled", func(t *testing.T) {
+		instance := NewStream(givenSilentStreamClient("{\"data\":{\"id\":\"1\"}}\r\n"), NewStreamResponseBodyReader())
+		ctx, cancel := context.WithCancel(context.Background())
+		if err := instance.StartStreamContext(ctx, nil); err != nil {
+			t.Fatalf("got err when starting stream %v", err)
+		}
+
+		<-instance.GetMessages()
+		cancel()
+		for range instance.GetMessages() {
+		}
+
+		if err := instance.Err(); err != context.Canceled {
+			t.Errorf("got err %v, want %v", err, context.Canceled)
+		}
+	})
+
+	t.Run("the context was already canceled", func(t *testing.T) {
+		instance := NewStream(givenSilentStreamClient(""), NewStreamResponseBodyReader())
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		if err := instance.StartStreamContext(ctx, nil); err != context.Canceled {
+			t.Errorf("got err %v, want %v", err, context.Canceled)
+		}
+	})
+}
+
+func TestClosedMessage(t *testing.T) {
+	t.Run("the last message is marked closed", func(t *testing.T) {
+		instance := NewStream(givenStreamClient("{\"data\":{\"id\":\"1\"}}\r\n"), NewStreamResponseBodyReader(), WithClosedMessage())
+
+		messages := drain(t, instance)
+
+		if len(messages) != 2 || messages[0].Closed {
+			t.Fatalf("got %+v, want a tweet then the closed message", messages)
+		}
+		if last := messages[1]; !last.Closed || last.Err != io.EOF || last.Err != instance.Err() {
+			t.Errorf("got %+v, want closed with %v", last, io.EOF)
+		}
+	})
+
+	t.Run("sent after StopStream", func(t *testing.T) {
+		instance := NewStream(givenSilentStreamClient("{\"data\":{\"id\":\"1\"}}\r\n"), NewStreamResponseBodyReader(), WithClosedMessage())
+		if err := instance.StartStream(nil); err != nil {
+			t.Fatalf("got err when starting stream %v", err)
+		}
+		if message := <-instance.GetMessages(); message.Err != nil || message.Closed {
+			t.Fatalf("got %+v, want the tweet", message)
+		}
+		instance.StopStream()
+
+		var messages []StreamMessage
+		for message := range instance.GetMessages() {
+			messages = append(messages, message)
+		}
+
+		if len(messages) != 1 || !messages[0].Closed || messages[0].Err != nil {
+			t.Errorf("got %+v, want only the closed message without an error", messages)
+		}
+	})
+
+	t.Run("not waited for by StopWithReport", func(t *testing.T) {
+		instance := NewStream(givenSilentStreamClient(""), NewStreamResponseBodyReader(), WithClosedMessage())
+		if err := instance.StartStream(nil); err != nil {
+			t.Fatalf("got err when starting stream %v", err)
+		}
+
+		stopped := make(chan struct{})
+		go func() {
+			instance.StopWithReport()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-time.After(time.Second):
+			t.Fatal("StopWithReport waited for the closed message to be read")
+		}
+	})
+
+	t.Run("off by default", func(t *testing.T) {
+		instance := NewStream(givenStreamClient("{\"data\":{\"id\":\"1\"}}\r\n"), NewStreamResponseBodyReader())
+
+		messages := drain(t, instance)
+
+		if len(messages) != 2 || messages[1].Closed || messages[1].Err != io.EOF {
+			t.Errorf("got %+v, want the error without Closed", messages)
+		}
+	})
+}
@@ -67,6 +67,17 @@ func (s *StreamQueryParamBuilder) Build() *url.Values {
 	return &query
 }
 
+// BuildMap returns the same query params as Build with each param name mapped to its comma-joined value.
+// It is meant for structured loggers that take flat fields.
+func (s *StreamQueryParamBuilder) BuildMap() map[string]string {
+	query := s.Build()
+	params := make(map[string]string, len(*query))
+	for name, values := range *query {
+		params[name] = strings.Join(values, ",")
+	}
+	return params
+}
+
 
 // AddExpansion adds an expansion defined in https://developer.twitter.com/en/docs/twitter-api/tweets/filtered-stream/api-reference/get-tweets-search-stream.
 // With expansions, developers can expand objects referenced in the payload. Objects available for expansion are referenced by ID.
@@ -0,0 +1,48 @@
+package stream
+
+// ClearExpansions removes every expansion added with `AddExpansion`. The backing array is reused.
+func (s *StreamQueryParamBuilder) ClearExpansions() *StreamQueryParamBuilder {
+	s.expansions = s.expansions[:0]
+	return s
+}
+
+// ClearMediaFields removes every media field added with `AddMediaField`. The backing array is reused.
+func (s *StreamQueryParamBuilder) ClearMediaFields() *StreamQueryParamBuilder {
+	s.mediaFields = s.mediaFields[:0]
+	return s
+}
+
+// ClearPlaceFields removes every place field added with `AddPlaceField`. The backing array is reused.
+func (s *StreamQueryParamBuilder) ClearPlaceFields() *StreamQueryParamBuilder {
+	s.placeFields = s.placeFields[:0]
+	return s
+}
+
+// ClearPollFields removes every poll field added with `AddPollField`. The backing array is reused.
+func (s *StreamQueryParamBuilder) ClearPollFields() *StreamQueryParamBuilder {
+	s.pollFields = s.pollFields[:0]
+	return s
+}
+
+// ClearTweetFields removes every tweet field added with `AddTweetField`. The backing array is reused.
+func (s *StreamQueryParamBuilder) ClearTweetFields() *StreamQueryParamBuilder {
+	s.tweetFields = s.tweetFields[:0]
+	return s
+}
+
+// ClearUserFields removes every user field added with `AddUserField`. The backing array is reused.
+func (s *StreamQueryParamBuilder) ClearUserFields() *StreamQueryParamBuilder {
+	s.userFields = s.userFields[:0]
+	return s
+}
+
+// Reset clears every expansion and field, and the backfill minutes, so the builder can be reused from scratch.
+func (s *StreamQueryParamBuilder) Reset() *StreamQueryParamBuilder {
+	s.backFillMinutes = 0
+	return s.ClearExpansions().
+		ClearMediaFields().
+		ClearPlaceFields().
+		ClearPollFields().
+		ClearTweetFields().
+		ClearUserFields()
+}
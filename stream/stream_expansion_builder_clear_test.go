@@ -0,0 +1,27 @@
+package stream
+
+import "testing"
+
+func TestClearMediaFieldsKeepsOtherFields(t *testing.T) {
+	builder := NewStreamQueryParamsBuilder().(*StreamQueryParamBuilder)
+	builder.AddMediaField("url").AddMediaField("preview_image_url").AddTweetField("created_at")
+
+	query := builder.ClearMediaFields().AddMediaField("width").Build()
+
+	if got := query.Get("media.fields"); got != "width" {
+		t.Errorf("got media.fields %q, want width", got)
+	}
+
+	if got := query.Get("tweet.fields"); got != "created_at" {
+		t.Errorf("got tweet.fields %q, want created_at", got)
+	}
+}
+
+func TestResetClearsEverything(t *testing.T) {
+	builder := NewStreamQueryParamsBuilder().(*StreamQueryParamBuilder)
+	builder.AddExpansion("author_id").AddUserField("username").AddPollField("options").AddPlaceField("geo").AddBackFillMinutes(2)
+
+	if query := builder.Reset().Build(); len(*query) != 0 {
+		t.Errorf("got %v, want no params", query.Encode())
+	}
+}
@@ -0,0 +1,59 @@
+package stream
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrEmptyCSVSegment is returned by the Set*CSV methods of the builder when a comma separated list has an empty entry.
+var ErrEmptyCSVSegment = errors.New("comma separated list has an empty entry")
+
+// SetExpansionsCSV replaces the expansions with a comma separated list, like "author_id,attachments.media_keys",
+// as it appears in Twitter's docs and config files. Spaces around entries are trimmed, and an empty string clears
+// the expansions. If an entry is empty, ErrEmptyCSVSegment is returned and the builder is left unchanged.
+func (s *StreamQueryParamBuilder) SetExpansionsCSV(csv string) (*StreamQueryParamBuilder, error) {
+	return s.setCSV(&s.expansions, csv)
+}
+
+// SetMediaFieldsCSV replaces the media fields with a comma separated list, like SetExpansionsCSV.
+func (s *StreamQueryParamBuilder) SetMediaFieldsCSV(csv string) (*StreamQueryParamBuilder, error) {
+	return s.setCSV(&s.mediaFields, csv)
+}
+
+// SetPlaceFieldsCSV replaces the place fields with a comma separated list, like SetExpansionsCSV.
+func (s *StreamQueryParamBuilder) SetPlaceFieldsCSV(csv string) (*StreamQueryParamBuilder, error) {
+	return s.setCSV(&s.placeFields, csv)
+}
+
+// SetPollFieldsCSV replaces the poll fields with a comma separated list, like SetExpansionsCSV.
+func (s *StreamQueryParamBuilder) SetPollFieldsCSV(csv string) (*StreamQueryParamBuilder, error) {
+	return s.setCSV(&s.pollFields, csv)
+}
+
+// SetTweetFieldsCSV replaces the tweet fields with a comma separated list, like SetExpansionsCSV.
+func (s *StreamQueryParamBuilder) SetTweetFieldsCSV(csv string) (*StreamQueryParamBuilder, error) {
+	return s.setCSV(&s.tweetFields, csv)
+}
+
+// SetUserFieldsCSV replaces the user fields with a comma separated list, like SetExpansionsCSV.
+func (s *StreamQueryParamBuilder) SetUserFieldsCSV(csv string) (*StreamQueryParamBuilder, error) {
+	return s.setCSV(&s.userFields, csv)
+}
+
+// setCSV parses a comma separated list and replaces a list of the builder with it.
+func (s *StreamQueryParamBuilder) setCSV(fields *[]*string, csv string) (*StreamQueryParamBuilder, error) {
+	var values []*string
+	if strings.TrimSpace(csv) != "" {
+		for i, segment := range strings.Split(csv, ",") {
+			value := strings.TrimSpace(segment)
+			if value == "" {
+				return s, fmt.Errorf("%w at position %d of %q", ErrEmptyCSVSegment, i, csv)
+			}
+			values = append(values, &value)
+		}
+	}
+
+	*fields = append((*fields)[:0], values...)
+	return s, nil
+}
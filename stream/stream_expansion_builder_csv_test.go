@@ -0,0 +1,62 @@
+package stream
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestSetExpansionsCSV(t *testing.T) {
+	var tests = []struct {
+		csv  string
+		want string
+		err  error
+	}{
+		{"author_id,attachments.media_keys", "author_id,attachments.media_keys", nil},
+		{" author_id , geo.place_id ", "author_id,geo.place_id", nil},
+		{"", "", nil},
+		{"author_id,,geo.place_id", "in_reply_to_user_id", ErrEmptyCSVSegment},
+		{"author_id,", "in_reply_to_user_id", ErrEmptyCSVSegment},
+	}
+
+	for i, tt := range tests {
+		testName := fmt.Sprintf("TestSetExpansionsCSV (%d)", i)
+
+		t.Run(testName, func(t *testing.T) {
+			builder := NewStreamQueryParamsBuilder().(*StreamQueryParamBuilder)
+			builder.AddExpansion("in_reply_to_user_id")
+
+			_, err := builder.SetExpansionsCSV(tt.csv)
+
+			if !errors.Is(err, tt.err) {
+				t.Errorf("got err %v, want %v", err, tt.err)
+			}
+			if got := builder.Build().Get("expansions"); got != tt.want {
+				t.Errorf("got expansions %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetFieldsCSVSetsEachCategory(t *testing.T) {
+	builder := NewStreamQueryParamsBuilder().(*StreamQueryParamBuilder)
+	builder.SetMediaFieldsCSV("url, width")
+	builder.SetPlaceFieldsCSV("geo")
+	builder.SetPollFieldsCSV("options")
+	builder.SetTweetFieldsCSV("created_at,lang")
+	builder.SetUserFieldsCSV("username")
+
+	query := builder.Build()
+
+	for param, want := range map[string]string{
+		"media.fields": "url,width",
+		"place.fields": "geo",
+		"poll.fields":  "options",
+		"tweet.fields": "created_at,lang",
+		"user.fields":  "username",
+	} {
+		if got := query.Get(param); got != want {
+			t.Errorf("got %s %q, want %q", param, got, want)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package stream
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrUnsupportedParam is returned by ValidateForEndpoint when the builder has params the endpoint doesn't support,
+// which Twitter would reject or silently ignore. Use errors.Is to detect it.
+var ErrUnsupportedParam = errors.New("query params not supported")
+
+// StreamEndpoint is a streaming endpoint of the v2 API that a builder's params can be validated for.
+type StreamEndpoint string
+
+const (
+	// FilteredStreamEndpoint is GET /2/tweets/search/stream, the endpoint Stream connects to.
+	FilteredStreamEndpoint StreamEndpoint = "filtered stream"
+	// SampleStreamEndpoint is GET /2/tweets/sample/stream, the 1% sample of all tweets. Stream doesn't connect to it,
+	// builders meant for both endpoints can be validated for it.
+	SampleStreamEndpoint StreamEndpoint = "sample stream"
+)
+
+// filteredStreamParams are the values each param of the filtered stream accepts, as documented at
+// https://developer.twitter.com/en/docs/twitter-api/tweets/filtered-stream/api-reference/get-tweets-search-stream.
+var filteredStreamParams = map[string][]string{
+	"expansions": {
+		"attachments.poll_ids", "attachments.media_keys", "author_id", "edit_history_tweet_ids",
+		"entities.mentions.username", "geo.place_id", "in_reply_to_user_id", "referenced_tweets.id",
+		"referenced_tweets.id.author_id",
+	},
+	"media.fields": {
+		"duration_ms", "height", "media_key", "preview_image_url", "type", "url", "width", "public_metrics",
+		"non_public_metrics", "organic_metrics", "promoted_metrics", "alt_text", "variants",
+	},
+	"place.fields": {"contained_within", "country", "country_code", "full_name", "geo", "id", "name", "place_type"},
+	"poll.fields":  {"duration_minutes", "end_datetime", "id", "options", "voting_status"},
+	"tweet.fields": {
+		"attachments", "author_id", "context_annotations", "conversation_id", "created_at", "edit_controls",
+		"edit_history_tweet_ids", "entities", "geo", "id", "in_reply_to_user_id", "lang", "non_public_metrics",
+		"public_metrics", "organic_metrics", "promoted_metrics", "possibly_sensitive", "referenced_tweets",
+		"reply_settings", "source", "text", "withheld", "note_tweet",
+	},
+	"user.fields": {
+		"created_at", "description", "entities", "id", "location", "name", "pinned_tweet_id", "profile_image_url",
+		"protected", "public_metrics", "url", "username", "verified", "verified_type", "withheld",
+	},
+}
+
+// endpointParams are the values each param accepts, by endpoint. A param missing from an endpoint isn't supported.
+var endpointParams = map[StreamEndpoint]map[string]map[string]bool{
+	FilteredStreamEndpoint: supportedParams(filteredStreamParams, nil),
+	// the sample stream takes the params of the filtered stream, but only app-only auth, so not the fields
+	// that need user context
+	SampleStreamEndpoint: supportedParams(filteredStreamParams, userContextFields),
+}
+
+// supportedParams indexes the values of every param, leaving out the `excluded` ones.
+func supportedParams(params map[string][]string, excluded map[string]bool) map[string]map[string]bool {
+	supported := make(map[string]map[string]bool, len(params))
+	for param, values := range params {
+		supported[param] = make(map[string]bool, len(values))
+		for _, value := range values {
+			if !excluded[value] {
+				supported[param][value] = true
+			}
+		}
+	}
+	return supported
+}
+
+// ValidateForEndpoint returns ErrUnsupportedParam, naming every param and value `endpoint` doesn't support,
+// e.g. when a builder made for the filtered stream is reused for the sample stream. The sample stream supports
+// the same params as the filtered stream except the fields that need user-context auth, such as
+// `organic_metrics`, since it only accepts app-only auth. Values are checked against the ones Twitter documents,
+// so values added to the API after this version of the library are reported too.
+// `backfill_minutes` is supported by both endpoints.
+func (s *StreamQueryParamBuilder) ValidateForEndpoint(endpoint StreamEndpoint) error {
+	supported, ok := endpointParams[endpoint]
+	if !ok {
+		return fmt.Errorf("%w: unknown endpoint %q", ErrUnsupportedParam, endpoint)
+	}
+
+	var unsupported []string
+	for _, param := range []struct {
+		name   string
+		values []*string
+	}{
+		{"expansions", s.expansions},
+		{"media.fields", s.mediaFields},
+		{"place.fields", s.placeFields},
+		{"poll.fields", s.pollFields},
+		{"tweet.fields", s.tweetFields},
+		{"user.fields", s.userFields},
+	} {
+		var values []string
+		for _, value := range param.values {
+			if !supported[param.name][*value] {
+				values = append(values, *value)
+			}
+		}
+		if len(values) > 0 {
+			unsupported = append(unsupported, fmt.Sprintf("%s=%s", param.name, strings.Join(values, ",")))
+		}
+	}
+
+	if len(unsupported) > 0 {
+		return fmt.Errorf("%w by the %s: %s", ErrUnsupportedParam, endpoint, strings.Join(unsupported, " "))
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package stream
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateForEndpoint(t *testing.T) {
+	tests := []struct {
+		name     string
+		build    func(b *StreamQueryParamBuilder)
+		endpoint StreamEndpoint
+		want     string
+	}{
+		{
+			name: "supported by the sample stream",
+			build: func(b *StreamQueryParamBuilder) {
+				b.WithRecommendedFields().AddMediaField("variants").AddBackFillMinutes(2)
+			},
+			endpoint: SampleStreamEndpoint,
+		},
+		{
+			name:     "user-context fields on the filtered stream",
+			build:    func(b *StreamQueryParamBuilder) { b.AddTweetField("organic_metrics") },
+			endpoint: FilteredStreamEndpoint,
+		},
+		{
+			name: "user-context fields on the sample stream",
+			build: func(b *StreamQueryParamBuilder) {
+				b.AddTweetField("lang").AddTweetField("organic_metrics").AddTweetField("promoted_metrics").AddMediaField("non_public_metrics")
+			},
+			endpoint: SampleStreamEndpoint,
+			want:     "query params not supported by the sample stream: media.fields=non_public_metrics tweet.fields=organic_metrics,promoted_metrics",
+		},
+		{
+			name:     "values neither endpoint supports",
+			build:    func(b *StreamQueryParamBuilder) { b.AddExpansion("author").AddUserField("followers") },
+			endpoint: FilteredStreamEndpoint,
+			want:     "query params not supported by the filtered stream: expansions=author user.fields=followers",
+		},
+		{
+			name:     "unknown endpoint",
+			build:    func(b *StreamQueryParamBuilder) {},
+			endpoint: "firehose",
+			want:     `query params not supported: unknown endpoint "firehose"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := NewStreamQueryParamsBuilder().(*StreamQueryParamBuilder)
+			tt.build(builder)
+
+			err := builder.ValidateForEndpoint(tt.endpoint)
+
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("got err %v, want none", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrUnsupportedParam) || err.Error() != tt.want {
+				t.Errorf("got err %v, want %s", err, tt.want)
+			}
+		})
+	}
+}
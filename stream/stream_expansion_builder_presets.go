@@ -0,0 +1,50 @@
+package stream
+
+// AddAuthorWithPinnedTweet requests the author of every tweet, along with the id of the tweet they pinned.
+// It adds, skipping any already added:
+//
+//   - the `author_id` expansion, so the author is in `includes.users`
+//   - the `username` and `pinned_tweet_id` user fields on that author
+//
+// The filtered stream can't expand the pinned tweet itself, as there is no `author_id.pinned_tweet_id` expansion
+// for tweets. Read the id from tweet.User.PinnedTweetID and hydrate it with a tweet lookup if you need it.
+func (s *StreamQueryParamBuilder) AddAuthorWithPinnedTweet() *StreamQueryParamBuilder {
+	addUniqueField(&s.expansions, "author_id")
+	addUniqueField(&s.userFields, "username")
+	addUniqueField(&s.userFields, "pinned_tweet_id")
+	return s
+}
+
+// recommendedTweetFields and recommendedExpansions are the fields added by WithRecommendedFields, in order.
+var (
+	recommendedTweetFields = []string{"created_at", "author_id", "conversation_id", "lang", "public_metrics", "referenced_tweets"}
+	recommendedExpansions  = []string{"author_id", "referenced_tweets.id"}
+)
+
+// WithRecommendedFields requests a small set of fields that most consumers need, as a starting point.
+// It adds, skipping any already added:
+//
+//   - the `created_at`, `author_id`, `conversation_id`, `lang`, `public_metrics` and `referenced_tweets` tweet fields
+//   - the `author_id` expansion, so the author is in `includes.users`
+//   - the `referenced_tweets.id` expansion, so retweeted, quoted and replied to tweets are in `includes.tweets`
+//
+// The set only changes in major versions. Add any other field you need with the other methods of the builder.
+func (s *StreamQueryParamBuilder) WithRecommendedFields() *StreamQueryParamBuilder {
+	for _, field := range recommendedTweetFields {
+		addUniqueField(&s.tweetFields, field)
+	}
+	for _, expansion := range recommendedExpansions {
+		addUniqueField(&s.expansions, expansion)
+	}
+	return s
+}
+
+// addUniqueField adds a value to a list of the builder unless it is already in it.
+func addUniqueField(fields *[]*string, value string) {
+	for _, field := range *fields {
+		if *field == value {
+			return
+		}
+	}
+	*fields = append(*fields, &value)
+}
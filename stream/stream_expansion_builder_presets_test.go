@@ -0,0 +1,37 @@
+package stream
+
+import "testing"
+
+func TestAddAuthorWithPinnedTweet(t *testing.T) {
+	builder := NewStreamQueryParamsBuilder().(*StreamQueryParamBuilder)
+	builder.AddExpansion("author_id").AddUserField("created_at")
+
+	query := builder.AddAuthorWithPinnedTweet().Build()
+
+	if got := query.Get("expansions"); got != "author_id" {
+		t.Errorf("got expansions %q, want author_id once", got)
+	}
+
+	if got := query.Get("user.fields"); got != "created_at,username,pinned_tweet_id" {
+		t.Errorf("got user.fields %q, want created_at,username,pinned_tweet_id", got)
+	}
+}
+
+func TestWithRecommendedFields(t *testing.T) {
+	builder := NewStreamQueryParamsBuilder().(*StreamQueryParamBuilder)
+	builder.AddTweetField("lang").AddUserField("username")
+
+	query := builder.WithRecommendedFields().Build()
+
+	if got := query.Get("tweet.fields"); got != "lang,created_at,author_id,conversation_id,public_metrics,referenced_tweets" {
+		t.Errorf("got tweet.fields %q, want the recommended fields added once", got)
+	}
+
+	if got := query.Get("expansions"); got != "author_id,referenced_tweets.id" {
+		t.Errorf("got expansions %q, want author_id,referenced_tweets.id", got)
+	}
+
+	if got := query.Get("user.fields"); got != "username" {
+		t.Errorf("got user.fields %q, want username untouched", got)
+	}
+}
@@ -25,4 +25,18 @@ func TestStreamQueryParamsBuilderBuildsQueryParams(t *testing.T) {
 		t.Errorf("ahh")
 	}
 
-}
\ No newline at end of file
+}
+func TestStreamQueryParamsBuilderBuildsMap(t *testing.T) {
+	builder := NewStreamQueryParamsBuilder().(*StreamQueryParamBuilder)
+
+	result := builder.
+		AddExpansion("author_id").
+		AddTweetField("created_at").
+		AddTweetField("lang").
+		AddBackFillMinutes(2).
+		BuildMap()
+
+	if len(result) != 3 || result["expansions"] != "author_id" || result["tweet.fields"] != "created_at,lang" || result["backfill_minutes"] != "2" {
+		t.Errorf("got %v, want expansions, tweet.fields, and backfill_minutes", result)
+	}
+}
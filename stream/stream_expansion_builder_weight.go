@@ -0,0 +1,84 @@
+package stream
+
+// HeavyPayloadWeight is the PayloadWeight above which a configuration is bandwidth-heavy: roughly ten times the
+// weight of a tweet with the default fields.
+const HeavyPayloadWeight = 100
+
+// unknownExpansionWeight is the weight of an expansion PayloadWeight doesn't know.
+const unknownExpansionWeight = 5
+
+// Weights of PayloadWeight, in points, where a tweet with the default `id` and `text` fields weighs 10.
+// They are rough relative sizes of typical payloads, not measurements.
+var (
+	objectWeights = map[string]int{"tweet": 10, "user": 8, "media": 5, "poll": 4, "place": 4}
+
+	fieldWeights = map[string]map[string]int{
+		"tweet": {
+			"context_annotations": 30,
+			"note_tweet":          20,
+			"entities":            15,
+			"organic_metrics":     6,
+			"promoted_metrics":    6,
+			"public_metrics":      4,
+			"non_public_metrics":  4,
+		},
+		"user":  {"entities": 10, "description": 5, "public_metrics": 3, "profile_image_url": 2},
+		"media": {"variants": 20, "alt_text": 3, "public_metrics": 3},
+		"poll":  {"options": 3},
+		"place": {"geo": 5},
+	}
+
+	// expansionObjects are the objects each expansion adds to `includes`, and about how many of them.
+	expansionObjects = map[string]struct {
+		object string
+		count  int
+	}{
+		"author_id":                      {"user", 1},
+		"in_reply_to_user_id":            {"user", 1},
+		"entities.mentions.username":     {"user", 2},
+		"referenced_tweets.id.author_id": {"user", 1},
+		"referenced_tweets.id":           {"tweet", 1},
+		"edit_history_tweet_ids":         {"tweet", 1},
+		"attachments.media_keys":         {"media", 2},
+		"attachments.poll_ids":           {"poll", 1},
+		"geo.place_id":                   {"place", 1},
+	}
+)
+
+// PayloadWeight estimates how heavy every streamed tweet will be with the requested fields and expansions,
+// in points where a tweet with the default fields weighs 10. It is a heuristic meant to compare configurations
+// and warn about costly ones, e.g. `weight > stream.HeavyPayloadWeight`, not a size in bytes: actual payloads
+// depend on the tweets. Heavy fields such as `context_annotations` or the media `variants` weigh the most, and
+// every expansion adds its objects, with their own requested fields, to the weight. Fields of objects that no
+// expansion includes, such as user fields without a user expansion, weigh nothing since Twitter doesn't send them.
+func (s *StreamQueryParamBuilder) PayloadWeight() int {
+	objects := map[string]int{"tweet": 1}
+	weight := 0
+	for _, expansion := range s.expansions {
+		if included, ok := expansionObjects[*expansion]; ok {
+			objects[included.object] += included.count
+		} else {
+			weight += unknownExpansionWeight
+		}
+	}
+
+	lists := map[string][]*string{
+		"tweet": s.tweetFields,
+		"user":  s.userFields,
+		"media": s.mediaFields,
+		"poll":  s.pollFields,
+		"place": s.placeFields,
+	}
+	for object, count := range objects {
+		objectWeight := objectWeights[object]
+		for _, field := range lists[object] {
+			if fieldWeight, ok := fieldWeights[object][*field]; ok {
+				objectWeight += fieldWeight
+			} else {
+				objectWeight++
+			}
+		}
+		weight += count * objectWeight
+	}
+	return weight
+}
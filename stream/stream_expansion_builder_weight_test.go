@@ -0,0 +1,63 @@
+package stream
+
+import "testing"
+
+func TestPayloadWeight(t *testing.T) {
+	tests := []struct {
+		name  string
+		build func(b *StreamQueryParamBuilder)
+		want  int
+	}{
+		{name: "default fields", build: func(b *StreamQueryParamBuilder) {}, want: 10},
+		{name: "user fields without a user expansion", build: func(b *StreamQueryParamBuilder) { b.AddUserField("description") }, want: 10},
+		{
+			name: "author with fields",
+			build: func(b *StreamQueryParamBuilder) {
+				b.AddExpansion("author_id").AddUserField("description").AddUserField("username")
+			},
+			// tweet 10, user 8 + description 5 + username 1
+			want: 24,
+		},
+		{
+			name: "referenced tweets carry the tweet fields",
+			build: func(b *StreamQueryParamBuilder) {
+				b.AddTweetField("public_metrics").AddExpansion("referenced_tweets.id")
+			},
+			want: 2 * (10 + 4),
+		},
+		{
+			name: "media variants",
+			build: func(b *StreamQueryParamBuilder) {
+				b.AddExpansion("attachments.media_keys").AddMediaField("variants")
+			},
+			want: 10 + 2*(5+20),
+		},
+		{name: "unknown expansion", build: func(b *StreamQueryParamBuilder) { b.AddExpansion("something.new") }, want: 15},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := NewStreamQueryParamsBuilder().(*StreamQueryParamBuilder)
+			tt.build(builder)
+			if got := builder.PayloadWeight(); got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("recommended fields are not heavy", func(t *testing.T) {
+		builder := NewStreamQueryParamsBuilder().(*StreamQueryParamBuilder).WithRecommendedFields()
+		if got := builder.PayloadWeight(); got > HeavyPayloadWeight {
+			t.Errorf("got %d, want at most %d", got, HeavyPayloadWeight)
+		}
+	})
+
+	t.Run("annotations and media are heavy", func(t *testing.T) {
+		builder := NewStreamQueryParamsBuilder().(*StreamQueryParamBuilder).WithRecommendedFields().
+			AddTweetField("context_annotations").AddTweetField("entities").
+			AddExpansion("attachments.media_keys").AddMediaField("variants")
+		if got := builder.PayloadWeight(); got <= HeavyPayloadWeight {
+			t.Errorf("got %d, want more than %d", got, HeavyPayloadWeight)
+		}
+	})
+}
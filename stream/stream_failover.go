@@ -0,0 +1,65 @@
+package stream
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+
+	"dev.freespoke.com/twitter-stream/httpclient"
+)
+
+// WithFailoverClients adds clients with other credentials, such as another app's bearer token, to fail over to.
+// Whenever connecting is rejected with httpclient.ErrUnauthorized or httpclient.ErrConnectionLimit, the next client
+// is tried, in the order given and wrapping around to the client passed to NewStream. Each client is tried at most
+// once per connection attempt, and the one that connects stays active until its credentials are rejected too.
+// This applies to the first connection and, with WithAutoReconnect, to every reconnect.
+//
+// Rules are stored per app by Twitter, so every app must have the same rules for failover to be transparent.
+func WithFailoverClients(clients ...httpclient.IHttpClient) Option {
+	return func(s *Stream) {
+		if len(s.clients) == 0 {
+			s.clients = []httpclient.IHttpClient{s.httpClient}
+		}
+		s.clients = append(s.clients, clients...)
+	}
+}
+
+// isCredentialError returns true if err means the active credentials can't be used to stream right now.
+func isCredentialError(err error) bool {
+	return errors.Is(err, httpclient.ErrUnauthorized) || errors.Is(err, httpclient.ErrConnectionLimit)
+}
+
+// client returns the client the stream currently connects with.
+func (s *Stream) client() httpclient.IHttpClient {
+	s.clientMu.Lock()
+	defer s.clientMu.Unlock()
+	return s.httpClient
+}
+
+// nextClient makes the next failover client active and returns it.
+func (s *Stream) nextClient() httpclient.IHttpClient {
+	s.clientMu.Lock()
+	defer s.clientMu.Unlock()
+	s.clientIndex = (s.clientIndex + 1) % len(s.clients)
+	s.httpClient = s.clients[s.clientIndex]
+	return s.httpClient
+}
+
+// dial makes the HTTP GET request to twitter with the active client, failing over to the other clients
+// when its credentials are rejected.
+func (s *Stream) dial(queryParams *url.Values) (*http.Response, error) {
+	res, err := s.client().GetSearchStream(queryParams)
+
+	for i := 1; err != nil && i < len(s.clients) && isCredentialError(err); i++ {
+		log.Printf("Credentials were rejected: %v. Failing over to the next credentials", err)
+		res, err = s.nextClient().GetSearchStream(queryParams)
+	}
+
+	var rateLimit *httpclient.RateLimitError
+	if errors.As(err, &rateLimit) {
+		s.stats.addRateLimited()
+	}
+
+	return res, err
+}
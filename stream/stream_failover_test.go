@@ -0,0 +1,71 @@
+package stream
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"dev.freespoke.com/twitter-stream/httpclient"
+)
+
+func givenRejectingClient(err error, calls *int) httpclient.IHttpClient {
+	mockClient := httpclient.NewHttpClientMock("foobar")
+	mockClient.MockGetSearchStream = func(queryParams *url.Values) (*http.Response, error) {
+		*calls++
+		return nil, err
+	}
+	return mockClient
+}
+
+func TestFailoverClients(t *testing.T) {
+	var tests = []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"unauthorized", fmt.Errorf("%w: revoked", httpclient.ErrUnauthorized), 1},
+		{"connection limit", fmt.Errorf("%w: too many", httpclient.ErrConnectionLimit), 1},
+		{"other errors don't fail over", errors.New("503"), 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int
+			instance := NewStream(givenRejectingClient(tt.err, &calls), NewStreamResponseBodyReader(),
+				WithFailoverClients(givenStreamClient("1\r\n")))
+
+			err := instance.StartStream(nil)
+			messages := 0
+			if err == nil {
+				for range instance.GetMessages() {
+					messages++
+				}
+			}
+
+			if calls != 1 {
+				t.Errorf("got %d calls to the primary client, want 1", calls)
+			}
+			// one message and io.EOF
+			if messages != tt.want*2 {
+				t.Errorf("got %d messages, want %d", messages, tt.want*2)
+			}
+		})
+	}
+}
+
+func TestFailoverTriesEveryClientOnce(t *testing.T) {
+	var primary, secondary int
+	unauthorized := fmt.Errorf("%w: revoked", httpclient.ErrUnauthorized)
+	instance := NewStream(givenRejectingClient(unauthorized, &primary), NewStreamResponseBodyReader(),
+		WithFailoverClients(givenRejectingClient(unauthorized, &secondary)))
+
+	if err := instance.StartStream(nil); !errors.Is(err, httpclient.ErrUnauthorized) {
+		t.Errorf("got %v, want %v", err, httpclient.ErrUnauthorized)
+	}
+
+	if primary != 1 || secondary != 1 {
+		t.Errorf("got %d and %d calls, want 1 and 1", primary, secondary)
+	}
+}
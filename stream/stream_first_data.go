@@ -0,0 +1,21 @@
+package stream
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrNoDataAfterConnect is returned when a connection was established with a 200, but nothing arrived on it,
+// not even a keep-alive, within the WithFirstDataTimeout window. This usually means a proxy is buffering the response.
+var ErrNoDataAfterConnect = errors.New("connection established but no data arrived")
+
+// WithFirstDataTimeout ends a connection with ErrNoDataAfterConnect when nothing arrives on it within `d` of
+// connecting. Twitter sends a keep-alive every 20 seconds, so a window of 30 seconds or more avoids false positives.
+// This is distinct from a stall later in the stream: it points at something between you and Twitter, like a proxy,
+// buffering the response. With WithAutoReconnect, the stream reconnects as for any other read error.
+// Defaults to 0, which never times out.
+func WithFirstDataTimeout(d time.Duration) Option {
+	return func(s *Stream) {
+		s.firstDataTimeout = d
+	}
+}
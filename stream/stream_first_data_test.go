@@ -0,0 +1,54 @@
+package stream
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"dev.freespoke.com/twitter-stream/httpclient"
+)
+
+func givenSilentStreamClient(first string) httpclient.IHttpClient {
+	mockClient := httpclient.NewHttpClientMock("foobar")
+	mockClient.MockGetSearchStream = func(queryParams *url.Values) (*http.Response, error) {
+		reader, writer := io.Pipe()
+		go writer.Write([]byte(first))
+		return &http.Response{StatusCode: http.StatusOK, Body: reader}, nil
+	}
+	return mockClient
+}
+
+func TestFirstDataTimeout(t *testing.T) {
+	t.Run("no data after connecting", func(t *testing.T) {
+		instance := NewStream(givenSilentStreamClient(""), NewStreamResponseBodyReader(), WithFirstDataTimeout(20*time.Millisecond))
+
+		messages := drain(t, instance)
+
+		if len(messages) != 1 || !errors.Is(messages[0].Err, ErrNoDataAfterConnect) {
+			t.Errorf("got %+v, want %v", messages, ErrNoDataAfterConnect)
+		}
+	})
+
+	readers := map[string]func() IStreamResponseBodyReader{
+		"line reader":         NewStreamResponseBodyReader,
+		"json decoder reader": NewStreamJSONDecoderReader,
+	}
+	for name, newReader := range readers {
+		t.Run("a keep-alive arrived with the "+name, func(t *testing.T) {
+			instance := NewStream(givenSilentStreamClient("\r\n"), newReader(), WithFirstDataTimeout(20*time.Millisecond))
+			if err := instance.StartStream(nil); err != nil {
+				t.Fatalf("got err when starting stream %v", err)
+			}
+
+			select {
+			case message := <-instance.GetMessages():
+				t.Errorf("got %+v, want the connection kept open", message)
+			case <-time.After(60 * time.Millisecond):
+			}
+			instance.StopStream()
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package stream
+
+import (
+	"errors"
+	"net/url"
+	"time"
+)
+
+// ErrDurationElapsed is returned by StreamFor when the stream ran for the whole duration.
+var ErrDurationElapsed = errors.New("stream duration elapsed")
+
+// StreamFor starts the stream, runs it for `d`, then stops it and closes the messages channel.
+// It blocks until the stream has stopped, so read the messages channel from another goroutine or register
+// OnMessage, like collecting a sample for a few minutes. It returns ErrDurationElapsed when the time was up,
+// the read error that ended the stream early, nil if StopStream was called first, or the StartStream error.
+func (s *Stream) StreamFor(queryParams *url.Values, d time.Duration) error {
+	if err := s.StartStream(queryParams); err != nil {
+		return err
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		s.StopStream()
+		<-s.finished
+		return ErrDurationElapsed
+	case <-s.finished:
+		return s.Err()
+	}
+}
@@ -0,0 +1,37 @@
+package stream
+
+import (
+	"io"
+	"testing"
+	"time"
+)
+
+func TestStreamForStopsWhenTheDurationElapsed(t *testing.T) {
+	instance := NewSyntheticStream(100, func() []byte { return []byte(`{"data":{"id":"1"}}`) })
+
+	var messages int
+	instance.OnMessage(func(message StreamMessage) {
+		messages++
+	})
+
+	if err := instance.StreamFor(nil, 50*time.Millisecond); err != ErrDurationElapsed {
+		t.Errorf("got %v, want %v", err, ErrDurationElapsed)
+	}
+
+	if messages == 0 {
+		t.Errorf("expected messages while streaming")
+	}
+
+	if _, ok := <-instance.GetMessages(); ok {
+		t.Errorf("expected the messages channel to be closed")
+	}
+}
+
+func TestStreamForReturnsTheTerminalError(t *testing.T) {
+	instance := NewStream(givenStreamClient("1\r\n"), NewStreamResponseBodyReader())
+	instance.OnMessage(func(message StreamMessage) {})
+
+	if err := instance.StreamFor(nil, time.Hour); err != io.EOF {
+		t.Errorf("got %v, want %v", err, io.EOF)
+	}
+}
@@ -0,0 +1,64 @@
+package stream
+
+import "time"
+
+// Health is a snapshot of the state of a Stream, meant to be marshaled as is by a /healthz handler.
+type Health struct {
+	// Connected is true while the stream is reading from a connection, and false while it reconnects or once stopped.
+	Connected bool `json:"connected"`
+	// LastMessageAt is when the last message other than a keep-alive was read. It is zero until one is.
+	LastMessageAt time.Time `json:"last_message_at"`
+	// ReconnectCount counts the successful reconnects made by WithAutoReconnect, like Stats().Reconnects.
+	ReconnectCount int `json:"reconnect_count"`
+	// LastError is the error that closed the last connection, empty if none did.
+	LastError string `json:"last_error,omitempty"`
+}
+
+// Health returns the state of the stream, e.g. for a health check:
+//
+//	http.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+//		health := api.Stream.Health()
+//		if !health.Connected {
+//			w.WriteHeader(http.StatusServiceUnavailable)
+//		}
+//		json.NewEncoder(w).Encode(health)
+//	})
+func (s *Stream) Health() Health {
+	return s.stats.health()
+}
+
+// health returns the Health of the counters.
+func (st *streamStats) health() Health {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	return Health{
+		Connected:      st.connected,
+		LastMessageAt:  st.lastMessageAt,
+		ReconnectCount: int(st.reconnects),
+		LastError:      st.lastError,
+	}
+}
+
+// setConnected records that the stream is reading from a new connection.
+func (st *streamStats) setConnected() {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.connected = true
+}
+
+// setDisconnected records that the connection was closed, by `err` unless it is nil.
+func (st *streamStats) setDisconnected(err error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.connected = false
+	if err != nil {
+		st.lastError = err.Error()
+	}
+}
+
+// setLastMessage records when the last message was read.
+func (st *streamStats) setLastMessage(at time.Time) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.lastMessageAt = at
+}
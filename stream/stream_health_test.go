@@ -0,0 +1,51 @@
+package stream
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHealth(t *testing.T) {
+	var writer *io.PipeWriter
+	instance := NewStream(givenPipeStreamClient(&writer), NewStreamResponseBodyReader())
+
+	if health := instance.Health(); health.Connected || !health.LastMessageAt.IsZero() {
+		t.Errorf("got %+v before starting, want disconnected", health)
+	}
+
+	if err := instance.StartStream(nil); err != nil {
+		t.Fatalf("got err when starting stream %v", err)
+	}
+	if health := instance.Health(); !health.Connected || !health.LastMessageAt.IsZero() {
+		t.Errorf("got %+v, want connected without messages", health)
+	}
+
+	before := time.Now()
+	go writer.Write([]byte("\r\n{\"data\":{\"id\":\"1\"}}\r\n"))
+	<-instance.GetMessages()
+	if health := instance.Health(); !health.Connected || health.LastMessageAt.Before(before) {
+		t.Errorf("got %+v, want the time of the message", health)
+	}
+
+	writer.CloseWithError(errors.New("connection reset"))
+	for range instance.GetMessages() {
+	}
+	health := instance.Health()
+	if health.Connected || health.LastError != "connection reset" || health.ReconnectCount != 0 {
+		t.Errorf("got %+v, want disconnected with the error", health)
+	}
+
+	b, err := json.Marshal(health)
+	if err != nil {
+		t.Fatalf("got err %v", err)
+	}
+	for _, field := range []string{`"connected":false`, `"last_message_at":`, `"reconnect_count":0`, `"last_error":"connection reset"`} {
+		if !strings.Contains(string(b), field) {
+			t.Errorf("got %s, want %s", b, field)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package stream
+
+import (
+	"encoding/json"
+	"net/url"
+)
+
+// TweetIDUnmarshalHook extracts only `data.id` from a message, skipping the rest of the payload.
+// Messages without a tweet id, such as errors and operational messages, decode to an empty string.
+func TweetIDUnmarshalHook(bytes []byte) (interface{}, error) {
+	message := struct {
+		Data struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}{}
+	err := json.Unmarshal(bytes, &message)
+	return message.Data.ID, err
+}
+
+// StartIDStream starts the stream with TweetIDUnmarshalHook and delivers only the id of each matching tweet.
+// This is meant for consumers that hydrate tweets in a separate pipeline.
+// Messages without a tweet id, decode errors, and warnings are skipped. The channel is closed when the stream stops,
+// including when it stops because of an error; use Err to find out why.
+// The returned channel replaces GetMessages: it reads the messages channel itself, so don't also range over
+// GetMessages, or use Collect or OnMessage, or the messages would be split between them. It also replaces the
+// unmarshal hook set with SetUnmarshalHook, or by a TypedStream, with TweetIDUnmarshalHook.
+func (s *Stream) StartIDStream(queryParams *url.Values) (<-chan string, error) {
+	s.SetUnmarshalHook(TweetIDUnmarshalHook)
+
+	if err := s.StartStream(queryParams); err != nil {
+		return nil, err
+	}
+
+	ids := make(chan string)
+	go func() {
+		defer close(ids)
+		for message := range s.messages {
+			id, _ := message.Data.(string)
+			if message.Err != nil || id == "" {
+				continue
+			}
+
+			select {
+			case ids <- id:
+			case <-s.done:
+			}
+		}
+	}()
+
+	return ids, nil
+}
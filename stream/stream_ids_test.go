@@ -0,0 +1,26 @@
+package stream
+
+import "testing"
+
+func TestStartIDStreamDeliversOnlyTweetIDs(t *testing.T) {
+	body := "{\"data\":{\"id\":\"1\",\"text\":\"hello\"}}\r\n" +
+		"{\"errors\":[{\"title\":\"operational-disconnect\",\"disconnect_type\":\"reset\"}]}\r\n" +
+		"{\"errors\":[{\"title\":\"Invalid Request\"}]}\r\n" +
+		"not json\r\n" +
+		"{\"data\":{\"id\":\"2\",\"text\":\"world\"}}\r\n"
+
+	instance := NewStream(givenStreamClient(body), NewStreamResponseBodyReader())
+	ids, err := instance.StartIDStream(nil)
+	if err != nil {
+		t.Fatalf("got err when starting stream %v", err)
+	}
+
+	var result []string
+	for id := range ids {
+		result = append(result, id)
+	}
+
+	if len(result) != 2 || result[0] != "1" || result[1] != "2" {
+		t.Errorf("got %v, want [1 2]", result)
+	}
+}
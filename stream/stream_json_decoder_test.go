@@ -0,0 +1,74 @@
+package stream
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestStreamJSONDecoderReaderReturnsKeepAlives(t *testing.T) {
+	reader := NewStreamJSONDecoderReader()
+	reader.setStreamResponseBody(strings.NewReader("\r\n{\"data\":{\"id\":\"1\"}}\r\n\r\n\n{\"data\":{\"id\":\"2\"}}\r\n"))
+
+	expected := []string{"", `{"data":{"id":"1"}}`, "", "", `{"data":{"id":"2"}}`}
+	for _, e := range expected {
+		b, err := reader.readNext()
+		if err != nil {
+			t.Fatalf("got err %v", err)
+		}
+		if string(b) != e {
+			t.Errorf("got %s, want %s", string(b), e)
+		}
+	}
+
+	if _, err := reader.readNext(); err != io.EOF {
+		t.Errorf("got %v, want io.EOF", err)
+	}
+}
+
+func TestStreamJSONDecoderReaderRejectsInvalidJSON(t *testing.T) {
+	reader := NewStreamJSONDecoderReader()
+	reader.setStreamResponseBody(strings.NewReader("not json\r\n"))
+
+	if _, err := reader.readNext(); err == nil {
+		t.Errorf("Expected error, got nil")
+	}
+}
+
+func givenBenchmarkStreamBody() []byte {
+	message := `{"data":{"id":"1445880548472328192","text":"` + strings.Repeat("a", 280) + `"},"matching_rules":[{"id":"1","tag":"cats"}]}`
+	var body bytes.Buffer
+	for i := 0; i < 1000; i++ {
+		body.WriteString(message)
+		body.WriteString("\r\n")
+		if i%10 == 0 {
+			body.WriteString("\r\n")
+		}
+	}
+	return body.Bytes()
+}
+
+func benchmarkReader(b *testing.B, newReader func() IStreamResponseBodyReader) {
+	body := givenBenchmarkStreamBody()
+	b.SetBytes(int64(len(body)))
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		reader := newReader()
+		reader.setStreamResponseBody(bytes.NewReader(body))
+		for {
+			if _, err := reader.readNext(); err != nil {
+				break
+			}
+		}
+	}
+}
+
+func BenchmarkStreamResponseBodyReader(b *testing.B) {
+	benchmarkReader(b, NewStreamResponseBodyReader)
+}
+
+func BenchmarkStreamJSONDecoderReader(b *testing.B) {
+	benchmarkReader(b, NewStreamJSONDecoderReader)
+}
@@ -0,0 +1,13 @@
+package stream
+
+// WithLineProcessor runs `process` on every message as read, before it is checked for warnings, deduplicated,
+// or passed to the unmarshal hook, e.g. to redact fields or extract metrics. It returns the line to use instead,
+// which may be `line` modified in place; returning nil or an empty line drops the message, which then takes no
+// sequence number. `line` is only valid until `process` returns, so copy what you keep.
+// Keep-alives are skipped, and WithRawSink records lines before they are processed.
+// It runs on the read goroutine, so it must be fast: a slow processor delays reading the stream.
+func WithLineProcessor(process func(line []byte) []byte) Option {
+	return func(s *Stream) {
+		s.lineProcessor = process
+	}
+}
@@ -0,0 +1,29 @@
+package stream
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestLineProcessor(t *testing.T) {
+	body := "{\"data\":{\"id\":\"1\",\"text\":\"secret\"}}\r\n{\"data\":{\"id\":\"2\",\"text\":\"drop me\"}}\r\n"
+	instance := NewStream(givenStreamClient(body), NewStreamResponseBodyReader(), WithLineProcessor(func(line []byte) []byte {
+		if bytes.Contains(line, []byte("drop me")) {
+			return nil
+		}
+		return bytes.ReplaceAll(line, []byte("secret"), []byte("******"))
+	}))
+	instance.SetUnmarshalHook(func(b []byte) (interface{}, error) {
+		return string(b), nil
+	})
+
+	messages := drain(t, instance)
+
+	if len(messages) != 2 || messages[0].Data != "{\"data\":{\"id\":\"1\",\"text\":\"******\"}}" || messages[1].Err != io.EOF {
+		t.Fatalf("got %+v, want the redacted message then the end of the stream", messages)
+	}
+	if messages[1].Sequence != 2 {
+		t.Errorf("got sequence %d, want the dropped message to take no sequence number", messages[1].Sequence)
+	}
+}
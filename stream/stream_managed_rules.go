@@ -0,0 +1,37 @@
+package stream
+
+import (
+	"log"
+
+	"dev.freespoke.com/twitter-stream/httpclient"
+	"dev.freespoke.com/twitter-stream/rules"
+)
+
+// WithManagedRules makes sure the credentials the stream is connected with have exactly the `desired` rules.
+// They are applied with `rules.SetRules` every time the stream connects, including reconnects and failovers
+// to other credentials with WithFailoverClients, so the filter stays the same whichever app is streaming.
+// If the rules can't be applied when the stream starts, StartStream returns the error. On later connects
+// the error is logged and the stream keeps running with the rules the app already has.
+func WithManagedRules(desired rules.CreateRulesRequest) Option {
+	return func(s *Stream) {
+		s.managedRules = &desired
+	}
+}
+
+// ActiveClient returns the client the stream currently connects with. It only changes on failover.
+func (s *Stream) ActiveClient() httpclient.IHttpClient {
+	return s.client()
+}
+
+// applyManagedRules sets the managed rules, if any, on the active client.
+func (s *Stream) applyManagedRules() error {
+	if s.managedRules == nil {
+		return nil
+	}
+
+	_, err := rules.NewRules(s.client()).SetRules(*s.managedRules, false)
+	if err != nil {
+		log.Printf("Failed to apply managed rules: %v", err)
+	}
+	return err
+}
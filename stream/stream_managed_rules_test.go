@@ -0,0 +1,71 @@
+package stream
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"dev.freespoke.com/twitter-stream/httpclient"
+	"dev.freespoke.com/twitter-stream/rules"
+)
+
+// givenRulesStreamClient returns a stream client without rules that records the rules requests it receives.
+func givenRulesStreamClient(requests *[]string, addErr error) httpclient.IHttpClient {
+	mockClient := httpclient.NewHttpClientMock("foobar")
+	mockClient.MockGetSearchStream = func(queryParams *url.Values) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader("1\r\n")),
+		}, nil
+	}
+	mockClient.MockGetRules = func() (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"meta": {"sent": "today"}}`)),
+		}, nil
+	}
+	mockClient.MockAddRules = func(queryParams *url.Values, body string) (*http.Response, error) {
+		*requests = append(*requests, body)
+		if addErr != nil {
+			return nil, addErr
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"meta": {"sent": "today", "summary": {"created": 1}}}`)),
+		}, nil
+	}
+	return mockClient
+}
+
+func TestManagedRulesFollowFailover(t *testing.T) {
+	var calls int
+	var requests []string
+	primary := givenRejectingClient(fmt.Errorf("%w: revoked", httpclient.ErrUnauthorized), &calls)
+	desired := rules.NewRuleBuilder().AddRule("cat has:images", "cats").Build()
+
+	instance := NewStream(primary, NewStreamResponseBodyReader(),
+		WithFailoverClients(givenRulesStreamClient(&requests, nil)),
+		WithManagedRules(desired),
+	)
+	drain(t, instance)
+
+	if len(requests) != 1 || !strings.Contains(requests[0], "cat has:images") {
+		t.Errorf("got %v, want the managed rule created on the failover client", requests)
+	}
+}
+
+func TestManagedRulesErrorFailsStartStream(t *testing.T) {
+	addErr := errors.New("rules endpoint down")
+	var requests []string
+	desired := rules.NewRuleBuilder().AddRule("cat has:images", "cats").Build()
+
+	instance := NewStream(givenRulesStreamClient(&requests, addErr), NewStreamResponseBodyReader(), WithManagedRules(desired))
+
+	if err := instance.StartStream(nil); err != addErr {
+		t.Errorf("got %v, want %v", err, addErr)
+	}
+}
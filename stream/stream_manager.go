@@ -0,0 +1,170 @@
+package stream
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sync"
+	"sync/atomic"
+
+	"dev.freespoke.com/twitter-stream/httpclient"
+	"dev.freespoke.com/twitter-stream/rules"
+)
+
+// ErrUntaggedGroupRule is returned by StreamManager.Start when a rule of a StreamGroup has no tag,
+// because tags are how the tweets of a group are told apart. Use errors.Is to detect it.
+var ErrUntaggedGroupRule = errors.New("stream group rule has no tag")
+
+// ErrDuplicateGroupTag is returned by StreamManager.Start when a tag is used by rules of several groups,
+// which would deliver the tweets of those rules to each of the groups. Use errors.Is to detect it.
+var ErrDuplicateGroupTag = errors.New("stream group rule tag is used by another group")
+
+// ErrStreamManagerStarted is returned by StreamManager.Start when it was already called.
+var ErrStreamManagerStarted = errors.New("stream manager was already started")
+
+type (
+	// IStreamManager is the interface that the stream manager struct implements.
+	IStreamManager interface {
+		Start() error
+		Stop()
+		GetMessages() <-chan SourcedMessage
+	}
+
+	// StreamGroup is a set of rules streamed on its own connection, with its own query params.
+	// Use it to only request expansions and fields, such as media, for the rules that need them.
+	StreamGroup struct {
+		// Name is the Source of the messages of the group.
+		Name string
+		// Query is the query params of the group's connection, e.g. built with NewStreamQueryParamsBuilder.
+		Query *url.Values
+		// Rules are the rules of the group. Every rule must have a tag, unique across groups.
+		// A group without rules receives every tweet.
+		Rules rules.CreateRulesRequest
+		// UnmarshalHook unmarshals the messages of the group, see SetUnmarshalHook. Defaults to the raw bytes.
+		UnmarshalHook UnmarshalHook
+		// Options configure the group's Stream. Don't use WithManagedRules, the manager sets the rules.
+		Options []Option
+	}
+
+	// SourcedMessage is a message of a StreamManager, with the Name of the StreamGroup it was streamed by.
+	SourcedMessage struct {
+		Source string
+		StreamMessage
+	}
+
+	// StreamManager runs a connection per StreamGroup, and merges their messages onto one channel.
+	StreamManager struct {
+		httpClient httpclient.IHttpClient
+		groups     []StreamGroup
+		streams    []IStream
+		messages   chan SourcedMessage
+		done       chan struct{}
+		stopOnce   sync.Once
+		started    int32
+	}
+)
+
+// NewStreamManager creates a manager of a connection per group, all made with `httpClient`.
+// Rules belong to the app, so every connection receives the tweets of every group's rules, and each
+// connection only delivers those of its own group, found with WithTagFilter.
+// Every connection counts against the connection limit of the app, which is 1 for most access levels:
+// `httpclient.ErrConnectionLimit` is returned by Start when there are more groups than the app may connect.
+func NewStreamManager(httpClient httpclient.IHttpClient, groups ...StreamGroup) IStreamManager {
+	return &StreamManager{
+		httpClient: httpClient,
+		groups:     groups,
+		messages:   make(chan SourcedMessage),
+		done:       make(chan struct{}),
+	}
+}
+
+// Start replaces the rules of the app with the rules of every group, using `rules.SetRules`, then connects
+// every group. If a group fails to connect, the groups already connected are stopped and the error is returned.
+// When Start fails, the messages channel is closed. It may only be called once, and returns
+// ErrStreamManagerStarted after that.
+func (m *StreamManager) Start() error {
+	if !atomic.CompareAndSwapInt32(&m.started, 0, 1) {
+		return ErrStreamManagerStarted
+	}
+
+	desired := rules.CreateRulesRequest{}
+	var tags [][]string
+	groupOf := make(map[string]string)
+	for _, group := range m.groups {
+		var groupTags []string
+		for _, rule := range group.Rules.Add {
+			if rule == nil || rule.Tag == nil || *rule.Tag == "" {
+				return m.fail(fmt.Errorf("%w: group %q", ErrUntaggedGroupRule, group.Name))
+			}
+			if other, ok := groupOf[*rule.Tag]; ok && other != group.Name {
+				return m.fail(fmt.Errorf("%w: %q is used by groups %q and %q", ErrDuplicateGroupTag, *rule.Tag, other, group.Name))
+			}
+			groupOf[*rule.Tag] = group.Name
+			groupTags = append(groupTags, *rule.Tag)
+		}
+		desired.Add = append(desired.Add, group.Rules.Add...)
+		tags = append(tags, groupTags)
+	}
+
+	if len(desired.Add) > 0 {
+		if _, err := rules.NewRules(m.httpClient).SetRules(desired, false); err != nil {
+			return m.fail(err)
+		}
+	}
+
+	for i, group := range m.groups {
+		opts := append([]Option{WithTagFilter(tags[i]...)}, group.Options...)
+		stream := NewStream(m.httpClient, NewStreamResponseBodyReader(), opts...)
+		if group.UnmarshalHook != nil {
+			stream.SetUnmarshalHook(group.UnmarshalHook)
+		}
+		if err := stream.StartStream(group.Query); err != nil {
+			return m.fail(fmt.Errorf("group %q: %w", group.Name, err))
+		}
+		m.streams = append(m.streams, stream)
+	}
+
+	var wg sync.WaitGroup
+	for i, stream := range m.streams {
+		wg.Add(1)
+		go func(source string, stream IStream) {
+			defer wg.Done()
+			for message := range stream.GetMessages() {
+				select {
+				case m.messages <- SourcedMessage{Source: source, StreamMessage: message}:
+				case <-m.done:
+					return
+				}
+			}
+		}(m.groups[i].Name, stream)
+	}
+	go func() {
+		wg.Wait()
+		close(m.messages)
+	}()
+
+	return nil
+}
+
+// fail stops the groups already connected and closes the messages channel, returning `err`.
+func (m *StreamManager) fail(err error) error {
+	m.Stop()
+	close(m.messages)
+	return err
+}
+
+// Stop stops every connection. The messages channel is closed once all of them have stopped.
+// Calling it more than once is a no-op.
+func (m *StreamManager) Stop() {
+	m.stopOnce.Do(func() {
+		close(m.done)
+		for _, stream := range m.streams {
+			stream.StopStream()
+		}
+	})
+}
+
+// GetMessages returns the read-only channel of the messages of every group.
+func (m *StreamManager) GetMessages() <-chan SourcedMessage {
+	return m.messages
+}
@@ -0,0 +1,148 @@
+package stream
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"dev.freespoke.com/twitter-stream/httpclient"
+	"dev.freespoke.com/twitter-stream/rules"
+)
+
+func TestStreamManager(t *testing.T) {
+	body := "{\"data\":{\"id\":\"1\"},\"matching_rules\":[{\"id\":\"1\",\"tag\":\"cats\"}]}\r\n" +
+		"{\"data\":{\"id\":\"2\"},\"matching_rules\":[{\"id\":\"2\",\"tag\":\"dogs\"}]}\r\n"
+	var mu sync.Mutex
+	var queries []string
+	var requests []string
+	client := httpclient.NewHttpClientMock("foobar")
+	client.MockGetSearchStream = func(queryParams *url.Values) (*http.Response, error) {
+		mu.Lock()
+		queries = append(queries, queryParams.Encode())
+		mu.Unlock()
+		return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(body))}, nil
+	}
+	client.MockGetRules = func() (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(`{"meta": {"sent": "today"}}`))}, nil
+	}
+	client.MockAddRules = func(queryParams *url.Values, body string) (*http.Response, error) {
+		requests = append(requests, body)
+		return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(`{"meta": {"sent": "today"}}`))}, nil
+	}
+
+	stringHook := func(b []byte) (interface{}, error) { return string(b), nil }
+
+	instance := NewStreamManager(client,
+		StreamGroup{
+			Name:          "media",
+			Query:         NewStreamQueryParamsBuilder().AddExpansion("attachments.media_keys").Build(),
+			Rules:         rules.NewRuleBuilder().AddRule("cat has:images", "cats").Build(),
+			UnmarshalHook: stringHook,
+		},
+		StreamGroup{
+			Name:          "text",
+			Query:         NewStreamQueryParamsBuilder().AddTweetField("lang").Build(),
+			Rules:         rules.NewRuleBuilder().AddRule("dog", "dogs").Build(),
+			UnmarshalHook: stringHook,
+		},
+	)
+	if err := instance.Start(); err != nil {
+		t.Fatalf("got err when starting %v", err)
+	}
+
+	var got []string
+	for message := range instance.GetMessages() {
+		if message.Err == io.EOF {
+			continue
+		}
+		if message.Err != nil {
+			t.Fatalf("got err %v", message.Err)
+		}
+		got = append(got, message.Source+":"+message.Data.(string))
+	}
+	sort.Strings(got)
+	sort.Strings(queries)
+
+	if len(got) != 2 || !strings.HasPrefix(got[0], "media:") || !strings.Contains(got[0], `"id":"1"`) ||
+		!strings.HasPrefix(got[1], "text:") || !strings.Contains(got[1], `"id":"2"`) {
+		t.Errorf("got %v, want each group's tweet tagged by its group", got)
+	}
+	if len(queries) != 2 || queries[0] != "expansions=attachments.media_keys" || queries[1] != "tweet.fields=lang" {
+		t.Errorf("got queries %v, want one connection per group", queries)
+	}
+	if len(requests) != 1 || !strings.Contains(requests[0], "cat has:images") || !strings.Contains(requests[0], `"dog"`) {
+		t.Errorf("got %v, want the rules of every group created at once", requests)
+	}
+}
+
+func TestStreamManagerUntaggedRule(t *testing.T) {
+	var requests []string
+	instance := NewStreamManager(givenRulesStreamClient(&requests, nil),
+		StreamGroup{Name: "media", Rules: rules.NewRuleBuilder().AddRule("cat has:images", "").Build()},
+	)
+
+	if err := instance.Start(); !errors.Is(err, ErrUntaggedGroupRule) {
+		t.Errorf("got err %v, want %v", err, ErrUntaggedGroupRule)
+	}
+	if len(requests) != 0 {
+		t.Errorf("got %v, want no rules created", requests)
+	}
+	for range instance.GetMessages() {
+	}
+	if err := instance.Start(); !errors.Is(err, ErrStreamManagerStarted) {
+		t.Errorf("got err %v when starting again, want %v", err, ErrStreamManagerStarted)
+	}
+}
+
+func TestStreamManagerDuplicateTag(t *testing.T) {
+	var requests []string
+	instance := NewStreamManager(givenRulesStreamClient(&requests, nil),
+		StreamGroup{Name: "media", Rules: rules.NewRuleBuilder().AddRule("cat has:images", "cats").Build()},
+		StreamGroup{Name: "text", Rules: rules.NewRuleBuilder().AddRule("cat", "cats").Build()},
+	)
+
+	if err := instance.Start(); !errors.Is(err, ErrDuplicateGroupTag) {
+		t.Errorf("got err %v, want %v", err, ErrDuplicateGroupTag)
+	}
+	if len(requests) != 0 {
+		t.Errorf("got %v, want no rules created", requests)
+	}
+}
+
+func TestStreamManagerConnectFailure(t *testing.T) {
+	var mu sync.Mutex
+	connections := 0
+	client := httpclient.NewHttpClientMock("foobar")
+	client.MockGetSearchStream = func(queryParams *url.Values) (*http.Response, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if connections++; connections > 1 {
+			return nil, httpclient.ErrConnectionLimit
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader("1\r\n"))}, nil
+	}
+	instance := NewStreamManager(client, StreamGroup{Name: "media"}, StreamGroup{Name: "text"})
+
+	if err := instance.Start(); !errors.Is(err, httpclient.ErrConnectionLimit) {
+		t.Errorf("got err %v, want %v", err, httpclient.ErrConnectionLimit)
+	}
+
+	closed := make(chan struct{})
+	go func() {
+		for range instance.GetMessages() {
+		}
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatal("messages channel was not closed after Start failed")
+	}
+}
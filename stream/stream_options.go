@@ -0,0 +1,29 @@
+package stream
+
+import "errors"
+
+// Option configures a Stream created with NewStream.
+type Option func(*Stream)
+
+// WithInitialConnectRetries retries the first connection made by `StartStream` up to `n` times, waiting as decided
+// by WithBackoff, before giving up. This smooths over transient failures at startup, such as a brief 503 or DNS
+// not being ready yet. Fatal errors like `httpclient.ErrConnectionLimit` and `httpclient.ErrUnauthorized` are never retried.
+// Defaults to 0, which returns the first error.
+func WithInitialConnectRetries(n int) Option {
+	return func(s *Stream) {
+		s.initialConnectRetries = n
+	}
+}
+
+// ErrMessageTooLarge is the error that ends the connection when a message is longer than WithMaxMessageSize allows.
+var ErrMessageTooLarge = errors.New("stream message too large")
+
+// WithMaxMessageSize limits how many bytes of a single message are held in memory while it is read,
+// so a connection that never sends a delimiter can't use unbounded memory. A longer message ends the
+// connection with ErrMessageTooLarge, which is reconnected like any other read error.
+// It applies to the default line reader only. Defaults to 0, which is unlimited.
+func WithMaxMessageSize(n int) Option {
+	return func(s *Stream) {
+		s.maxMessageSize = n
+	}
+}
@@ -0,0 +1,73 @@
+package stream
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// MergeQueries merges query params built by several builders into one, e.g. base fields and experiment fields.
+// The comma separated lists of every param, such as "tweet.fields", are joined in order with duplicates removed.
+// "backfill_minutes" can only have one value, so the largest one wins. Nil queries are skipped.
+func MergeQueries(queries ...*url.Values) *url.Values {
+	merged := new(url.URL).Query()
+	var backFillMinutes int
+
+	lists := make(map[string][]string)
+	var params []string
+	for _, query := range queries {
+		if query == nil {
+			continue
+		}
+
+		for param, values := range *query {
+			if param == "backfill_minutes" {
+				for _, value := range values {
+					if minutes, err := strconv.Atoi(value); err == nil && minutes > backFillMinutes {
+						backFillMinutes = minutes
+					}
+				}
+				continue
+			}
+
+			if _, ok := lists[param]; !ok {
+				params = append(params, param)
+			}
+			for _, value := range values {
+				lists[param] = appendUnique(lists[param], strings.Split(value, ",")...)
+			}
+		}
+	}
+
+	for _, param := range params {
+		if len(lists[param]) > 0 {
+			merged.Add(param, strings.Join(lists[param], ","))
+		}
+	}
+	if backFillMinutes > 0 {
+		merged.Add("backfill_minutes", strconv.Itoa(backFillMinutes))
+	}
+
+	return &merged
+}
+
+// appendUnique appends the values that are not empty and not in the list yet.
+func appendUnique(list []string, values ...string) []string {
+	for _, value := range values {
+		if value == "" {
+			continue
+		}
+
+		found := false
+		for _, existing := range list {
+			if existing == value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, value)
+		}
+	}
+	return list
+}
@@ -0,0 +1,31 @@
+package stream
+
+import "testing"
+
+func TestMergeQueries(t *testing.T) {
+	base := NewStreamQueryParamsBuilder().
+		AddTweetField("created_at").
+		AddTweetField("author_id").
+		AddExpansion("author_id").
+		AddBackFillMinutes(2).
+		Build()
+	experiment := NewStreamQueryParamsBuilder().
+		AddTweetField("author_id").
+		AddTweetField("lang").
+		AddUserField("username").
+		AddBackFillMinutes(5).
+		Build()
+
+	result := MergeQueries(base, nil, experiment)
+
+	expected := "backfill_minutes=5&expansions=author_id&tweet.fields=created_at%2Cauthor_id%2Clang&user.fields=username"
+	if result.Encode() != expected {
+		t.Errorf("got %s, want %s", result.Encode(), expected)
+	}
+}
+
+func TestMergeQueriesWithoutQueries(t *testing.T) {
+	if result := MergeQueries(); len(*result) != 0 {
+		t.Errorf("got %v, want no params", result.Encode())
+	}
+}
@@ -0,0 +1,71 @@
+package stream
+
+import (
+	"compress/gzip"
+	"io"
+	"log"
+	"time"
+)
+
+// rawSinkFlushInterval is how often a gzip raw sink is flushed, bounding what a crash can lose.
+const rawSinkFlushInterval = time.Second
+
+var newline = []byte("\n")
+
+// rawSink archives the raw bytes of every message read, one message per line.
+// It is only used by the read goroutine.
+type rawSink struct {
+	w         io.Writer
+	gzip      *gzip.Writer
+	lastFlush time.Time
+}
+
+// WithRawSink writes the raw bytes of every message read to `w` as newline delimited JSON, before it is decoded.
+// Keep-alives are not written. Writes happen on the read goroutine, so `w` should be fast, e.g. a buffered file.
+// Write errors are logged and don't stop the stream.
+func WithRawSink(w io.Writer) Option {
+	return func(s *Stream) {
+		s.rawSink = &rawSink{w: w}
+	}
+}
+
+// WithRawSinkGzip is WithRawSink with the archive gzip compressed as it is written.
+// The gzip stream is flushed at least every second while messages or keep-alives arrive, so a crash loses little,
+// and it is closed when the stream stops, which writes the gzip footer. `w` itself is not closed.
+func WithRawSinkGzip(w io.Writer) Option {
+	return func(s *Stream) {
+		gz := gzip.NewWriter(w)
+		s.rawSink = &rawSink{w: gz, gzip: gz}
+	}
+}
+
+// write archives a message. An empty message is a keep-alive, which is only used to flush.
+func (r *rawSink) write(b []byte) {
+	if len(b) > 0 {
+		// b is the reader's buffer, so the newline is written separately rather than appended.
+		_, err := r.w.Write(b)
+		if err == nil {
+			_, err = r.w.Write(newline)
+		}
+		if err != nil {
+			log.Printf("Failed to write to the raw sink: %v", err)
+		}
+	}
+
+	if r.gzip != nil && time.Since(r.lastFlush) >= rawSinkFlushInterval {
+		r.lastFlush = time.Now()
+		if err := r.gzip.Flush(); err != nil {
+			log.Printf("Failed to flush the raw sink: %v", err)
+		}
+	}
+}
+
+// close flushes and closes the gzip stream, if any.
+func (r *rawSink) close() {
+	if r.gzip == nil {
+		return
+	}
+	if err := r.gzip.Close(); err != nil {
+		log.Printf("Failed to close the raw sink: %v", err)
+	}
+}
@@ -0,0 +1,42 @@
+package stream
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"testing"
+)
+
+func TestRawSink(t *testing.T) {
+	body := "{\"data\":{\"id\":\"1\"}}\r\n\r\n{\"data\":{\"id\":\"2\"}}\r\n"
+	want := "{\"data\":{\"id\":\"1\"}}\n{\"data\":{\"id\":\"2\"}}\n"
+
+	t.Run("plain", func(t *testing.T) {
+		var archive bytes.Buffer
+		instance := NewStream(givenStreamClient(body), NewStreamResponseBodyReader(), WithRawSink(&archive))
+		drain(t, instance)
+
+		if archive.String() != want {
+			t.Errorf("got %q, want %q", archive.String(), want)
+		}
+	})
+
+	t.Run("gzip", func(t *testing.T) {
+		var archive bytes.Buffer
+		instance := NewStream(givenStreamClient(body), NewStreamResponseBodyReader(), WithRawSinkGzip(&archive))
+		drain(t, instance)
+
+		reader, err := gzip.NewReader(&archive)
+		if err != nil {
+			t.Fatalf("archive is not gzip: %v", err)
+		}
+		got, err := io.ReadAll(reader)
+		if err != nil {
+			t.Fatalf("archive was not closed cleanly: %v", err)
+		}
+
+		if string(got) != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+	})
+}
@@ -0,0 +1,155 @@
+package stream
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+	"time"
+)
+
+// ErrStreamStopped is returned when an operation needs a stream that was not stopped yet.
+var ErrStreamStopped = errors.New("stream is stopped")
+
+// WithAutoReconnect reconnects with the backoff of WithBackoff whenever the connection drops, instead of
+// delivering the read error and closing the messages channel. The backoff is adjusted for the reason Twitter gave
+// for disconnecting, see WithReasonBackoff. The same messages channel keeps being used across reconnects. Reconnecting ends when StopStream is called, or when connecting fails with a fatal error
+// such as httpclient.ErrConnectionLimit or httpclient.ErrUnauthorized, which is then delivered as the last message.
+func WithAutoReconnect() Option {
+	return func(s *Stream) {
+		s.autoReconnect = true
+	}
+}
+
+// WithRetryUnauthorized keeps retrying connections rejected with httpclient.ErrUnauthorized, for example when the
+// token is expected to be rotated. By default a 401 or 403 is fatal, so the stream stops with the error instead of
+// hammering an endpoint that will never accept the credentials.
+func WithRetryUnauthorized() Option {
+	return func(s *Stream) {
+		s.retryUnauthorized = true
+	}
+}
+
+// WithFreshConnectionOnReconnect closes the idle connections of the http client before every reconnect, so each
+// reconnect dials a brand-new TCP connection instead of reusing one. This helps behind NATs and proxies that
+// silently kill reused sockets. It needs a client with a CloseIdleConnections method, like the ones created by
+// httpclient.NewHttpClient. Defaults to false, which lets the transport reuse connections.
+func WithFreshConnectionOnReconnect(fresh bool) Option {
+	return func(s *Stream) {
+		s.freshConnection = fresh
+	}
+}
+
+// idleConnectionCloser is implemented by http clients that can drop their idle connections,
+// such as clients created with `httpclient.NewHttpClient`.
+type idleConnectionCloser interface {
+	CloseIdleConnections()
+}
+
+// ForceReconnect interrupts the backoff wait of a reconnect in progress, so the next attempt is made immediately.
+// It is meant for when the cause of the disconnect is known to be resolved, such as after a network blip.
+// It is a no-op while the stream is connected, and returns ErrStreamStopped once the stream was stopped.
+func (s *Stream) ForceReconnect() error {
+	if stopped(s.done) {
+		return ErrStreamStopped
+	}
+	if atomic.LoadInt32(&s.waiting) == 0 {
+		return nil
+	}
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+		// a wake up is already pending
+	}
+	return nil
+}
+
+// reconnect connects again until it succeeds, the stream is stopped, or a fatal error is returned.
+// It returns nil once connected or stopped.
+func (s *Stream) reconnect(queryParams *url.Values) error {
+	atomic.StoreInt32(&s.waiting, 1)
+	defer atomic.StoreInt32(&s.waiting, 0)
+
+	// drop a wake up left over from an earlier reconnect
+	select {
+	case <-s.wake:
+	default:
+	}
+
+	for attempt := 0; ; attempt++ {
+		delay := s.reconnectDelay(attempt)
+		log.Printf("Reconnecting stream in %v", delay)
+		if !s.sleep(delay) {
+			return nil
+		}
+
+		if s.freshConnection {
+			if client, ok := s.client().(idleConnectionCloser); ok {
+				client.CloseIdleConnections()
+			}
+		}
+
+		res, err := s.dial(queryParams)
+		if err == nil {
+			s.backoff.Reset()
+			s.stats.addReconnect()
+			s.applyManagedRules()
+			s.setBody(res)
+			return nil
+		}
+		if s.isFatal(err) {
+			return err
+		}
+		log.Printf("Failed to reconnect stream: %v", err)
+	}
+}
+
+// sleep waits for `d` and returns true, or returns false as soon as the stream is stopped.
+// ForceReconnect ends the wait early.
+func (s *Stream) sleep(d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-s.wake:
+		return true
+	case <-s.done:
+		return false
+	}
+}
+
+// setBody makes the body of `res` the connection messages are read from, closing the previous one.
+// If the stream was already stopped, `body` is closed instead and false is returned.
+func (s *Stream) setBody(res *http.Response) bool {
+	body := res.Body
+	s.bodyMu.Lock()
+	defer s.bodyMu.Unlock()
+
+	if stopped(s.done) {
+		body.Close()
+		return false
+	}
+
+	if s.body != nil {
+		s.body.Close()
+	}
+	s.body = body
+	s.trailer = res.Trailer
+	s.stats.setConnected()
+	s.reader.setStreamResponseBody(countingReader{reader: body, stats: &s.stats})
+	return true
+}
+
+// closeBody closes the connection messages are read from.
+func (s *Stream) closeBody() {
+	s.bodyMu.Lock()
+	defer s.bodyMu.Unlock()
+
+	if s.body != nil {
+		s.body.Close()
+	}
+}
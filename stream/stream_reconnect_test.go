@@ -0,0 +1,227 @@
+package stream
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"dev.freespoke.com/twitter-stream/httpclient"
+)
+
+// givenReconnectingClient returns a client whose every connection delivers a single numbered message,
+// until `connections` connections were made and `err` is returned.
+func givenReconnectingClient(connections int, err error) httpclient.IHttpClient {
+	var count int
+	mockClient := httpclient.NewHttpClientMock("foobar")
+	mockClient.MockGetSearchStream = func(queryParams *url.Values) (*http.Response, error) {
+		if count == connections {
+			return nil, err
+		}
+		count++
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(bytes.NewReader([]byte(fmt.Sprintf("%d\r\n", count)))),
+		}, nil
+	}
+	return mockClient
+}
+
+func TestAutoReconnect(t *testing.T) {
+	connectionLimit := fmt.Errorf("%w: too many", httpclient.ErrConnectionLimit)
+	instance := NewStream(givenReconnectingClient(3, connectionLimit), NewStreamResponseBodyReader(), WithAutoReconnect()).(*Stream)
+	instance.backoff = BackoffFunc(func(attempt int) time.Duration { return 0 })
+	instance.SetUnmarshalHook(func(b []byte) (interface{}, error) {
+		return string(b), nil
+	})
+
+	messages := drain(t, instance)
+
+	if len(messages) != 4 {
+		t.Fatalf("got %d messages, want 4", len(messages))
+	}
+	for i, message := range messages[:3] {
+		if want := fmt.Sprint(i + 1); message.Data != want {
+			t.Errorf("got %v, want %s", message.Data, want)
+		}
+	}
+	if messages[3].Err != connectionLimit {
+		t.Errorf("got %v, want %v", messages[3].Err, connectionLimit)
+	}
+}
+
+func TestAutoReconnectStopsWhenStopped(t *testing.T) {
+	instance := NewStream(givenReconnectingClient(1, nil), NewStreamResponseBodyReader(), WithAutoReconnect()).(*Stream)
+	instance.backoff = BackoffFunc(func(attempt int) time.Duration { return time.Hour })
+
+	if err := instance.StartStream(nil); err != nil {
+		t.Fatalf("got err when starting stream %v", err)
+	}
+
+	<-instance.GetMessages()
+	instance.StopStream()
+
+	if _, ok := <-instance.GetMessages(); ok {
+		t.Errorf("expected the messages channel to be closed")
+	}
+}
+
+func TestAutoReconnectStopsOnUnauthorized(t *testing.T) {
+	unauthorized := fmt.Errorf("%w: revoked", httpclient.ErrUnauthorized)
+	instance := NewStream(givenReconnectingClient(1, unauthorized), NewStreamResponseBodyReader(), WithAutoReconnect()).(*Stream)
+	instance.backoff = BackoffFunc(func(attempt int) time.Duration { return 0 })
+
+	messages := drain(t, instance)
+
+	if len(messages) != 2 || messages[1].Err != unauthorized {
+		t.Errorf("got %v, want one message and %v", messages, unauthorized)
+	}
+}
+
+func TestRetryUnauthorized(t *testing.T) {
+	var calls int
+	mockClient := httpclient.NewHttpClientMock("foobar")
+	mockClient.MockGetSearchStream = func(queryParams *url.Values) (*http.Response, error) {
+		calls++
+		switch calls {
+		case 2:
+			return nil, fmt.Errorf("%w: rotating", httpclient.ErrUnauthorized)
+		case 4:
+			return nil, fmt.Errorf("%w: too many", httpclient.ErrConnectionLimit)
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(bytes.NewReader([]byte("1\r\n"))),
+		}, nil
+	}
+
+	instance := NewStream(mockClient, NewStreamResponseBodyReader(), WithAutoReconnect(), WithRetryUnauthorized()).(*Stream)
+	instance.backoff = BackoffFunc(func(attempt int) time.Duration { return 0 })
+
+	messages := drain(t, instance)
+
+	// a message from the first and third connections, then the connection limit error
+	if len(messages) != 3 || !errors.Is(messages[2].Err, httpclient.ErrConnectionLimit) {
+		t.Errorf("got %v, want two messages and ErrConnectionLimit", messages)
+	}
+}
+
+func TestForceReconnectInterruptsBackoff(t *testing.T) {
+	instance := NewStream(givenReconnectingClient(2, nil), NewStreamResponseBodyReader(), WithAutoReconnect()).(*Stream)
+	instance.backoff = BackoffFunc(func(attempt int) time.Duration { return time.Hour })
+
+	if err := instance.ForceReconnect(); err != nil {
+		t.Errorf("got %v, want nil before the stream disconnects", err)
+	}
+
+	if err := instance.StartStream(nil); err != nil {
+		t.Fatalf("got err when starting stream %v", err)
+	}
+	<-instance.GetMessages()
+
+	received := make(chan StreamMessage)
+	go func() {
+		received <- <-instance.GetMessages()
+	}()
+
+	deadline := time.After(time.Second)
+	for {
+		if err := instance.ForceReconnect(); err != nil {
+			t.Fatalf("got %v, want nil", err)
+		}
+		select {
+		case <-received:
+			instance.StopStream()
+			if err := instance.ForceReconnect(); err != ErrStreamStopped {
+				t.Errorf("got %v, want %v", err, ErrStreamStopped)
+			}
+			return
+		case <-deadline:
+			t.Fatal("ForceReconnect did not interrupt the backoff")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+// closingClient counts the calls to CloseIdleConnections.
+type closingClient struct {
+	httpclient.IHttpClient
+	closed int
+}
+
+func (c *closingClient) CloseIdleConnections() {
+	c.closed++
+}
+
+func TestFreshConnectionOnReconnect(t *testing.T) {
+	for _, fresh := range []bool{true, false} {
+		client := &closingClient{IHttpClient: givenReconnectingClient(3, httpclient.ErrConnectionLimit)}
+		instance := NewStream(client, NewStreamResponseBodyReader(), WithAutoReconnect(), WithFreshConnectionOnReconnect(fresh)).(*Stream)
+		instance.backoff = BackoffFunc(func(attempt int) time.Duration { return 0 })
+
+		drain(t, instance)
+
+		want := 0
+		if fresh {
+			want = 3
+		}
+		if client.closed != want {
+			t.Errorf("got %d closes with fresh %v, want %d", client.closed, fresh, want)
+		}
+	}
+}
+
+func TestAutoReconnectKeepsTheMessagesChannel(t *testing.T) {
+	var writers []*io.PipeWriter
+	mockClient := httpclient.NewHttpClientMock("foobar")
+	mockClient.MockGetSearchStream = func(queryParams *url.Values) (*http.Response, error) {
+		reader, writer := io.Pipe()
+		writers = append(writers, writer)
+		go writer.Write([]byte(fmt.Sprintf("%d\r\n", len(writers))))
+		return &http.Response{StatusCode: http.StatusOK, Body: reader}, nil
+	}
+	instance := NewStream(mockClient, NewStreamResponseBodyReader(), WithAutoReconnect()).(*Stream)
+	instance.backoff = BackoffFunc(func(attempt int) time.Duration { return 0 })
+	instance.SetUnmarshalHook(func(b []byte) (interface{}, error) {
+		return string(b), nil
+	})
+	if err := instance.StartStream(nil); err != nil {
+		t.Fatalf("got err when starting stream %v", err)
+	}
+	messages := instance.GetMessages()
+
+	receive := func(want string) {
+		select {
+		case message, ok := <-messages:
+			if !ok {
+				t.Fatalf("messages channel closed, want %s", want)
+			}
+			if message.Data != want || message.Err != nil {
+				t.Errorf("got %+v, want %s", message, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("no message, want %s", want)
+		}
+	}
+
+	receive("1")
+	// the first connection drops mid-stream
+	writers[0].CloseWithError(io.ErrUnexpectedEOF)
+	receive("2")
+
+	if instance.GetMessages() != messages {
+		t.Error("got a new messages channel after reconnecting")
+	}
+	if got := instance.Stats().Reconnects; got != 1 {
+		t.Errorf("got %d reconnects, want 1", got)
+	}
+
+	instance.StopStream()
+	for range messages {
+	}
+}
@@ -0,0 +1,125 @@
+package stream
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// WithReplaySpeed paces a NewReplayStream by the "created_at" of its tweets: the gap between two tweets is
+// waited for, divided by `multiplier`, so 1 replays at the original timing and 10 ten times faster.
+// Tweets without a "created_at" are emitted immediately. A multiplier of 0 or less replays as fast as the
+// messages are read, which is the default. Other streams ignore it.
+func WithReplaySpeed(multiplier float64) Option {
+	return func(s *Stream) {
+		s.replaySpeed = multiplier
+	}
+}
+
+// NewReplayStream creates a stream that delivers the messages read from `r`, one JSON message per line, such as
+// a file written by WithRawSink or WithTagArchive, without any network. Messages go through the same reader,
+// unmarshal hook, and delivery path as a real stream, so recorded traffic can be used to test a consumer under
+// realistic load with WithReplaySpeed. Empty lines are skipped. Once `r` is exhausted the stream ends with
+// io.EOF, so don't use WithAutoReconnect.
+func NewReplayStream(r io.Reader, opts ...Option) IStream {
+	client := &replayClient{reader: bufio.NewReader(r)}
+	s := NewStream(client, NewStreamResponseBodyReader(), opts...)
+	client.speed = s.(*Stream).replaySpeed
+	return s
+}
+
+// replayClient is an http client whose stream response replays a reader. Other requests fail like those of a
+// synthetic stream.
+type replayClient struct {
+	syntheticClient
+	reader *bufio.Reader
+	speed  float64
+}
+
+func (c *replayClient) GetSearchStream(queryParams *url.Values) (*http.Response, error) {
+	body := &replayBody{reader: c.reader, speed: c.speed, closed: make(chan struct{})}
+	return &http.Response{StatusCode: http.StatusOK, Body: body}, nil
+}
+
+// replayBody is a response body that replays the lines of a reader, terminated by "\r\n", paced by their created_at.
+type replayBody struct {
+	reader    *bufio.Reader
+	speed     float64
+	last      time.Time
+	pending   []byte
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func (b *replayBody) Read(p []byte) (int, error) {
+	for len(b.pending) == 0 {
+		if stopped(b.closed) {
+			return 0, io.EOF
+		}
+		line, err := b.reader.ReadBytes('\n')
+		line = bytes.TrimRight(line, "\r\n")
+		if len(line) > 0 {
+			b.wait(line)
+			b.pending = append(line, '\r', '\n')
+		} else if err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, b.pending)
+	b.pending = b.pending[n:]
+	return n, nil
+}
+
+// wait sleeps for the gap between the created_at of the previous tweet and that of `line`, divided by the speed.
+func (b *replayBody) wait(line []byte) {
+	if b.speed <= 0 {
+		return
+	}
+	createdAt := replayCreatedAt(line)
+	if createdAt.IsZero() {
+		return
+	}
+
+	previous := b.last
+	b.last = createdAt
+	if previous.IsZero() || !createdAt.After(previous) {
+		return
+	}
+
+	timer := time.NewTimer(time.Duration(float64(createdAt.Sub(previous)) / b.speed))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-b.closed:
+	}
+}
+
+// replayCreatedAt returns the "created_at" of the tweet of a message, or of a message unwrapped by WithDataOnly.
+func replayCreatedAt(line []byte) time.Time {
+	var message struct {
+		CreatedAt time.Time `json:"created_at"`
+		Data      struct {
+			CreatedAt time.Time `json:"created_at"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(line, &message); err != nil {
+		return time.Time{}
+	}
+	if message.Data.CreatedAt.IsZero() {
+		return message.CreatedAt
+	}
+	return message.Data.CreatedAt
+}
+
+func (b *replayBody) Close() error {
+	b.closeOnce.Do(func() {
+		close(b.closed)
+	})
+	return nil
+}
@@ -0,0 +1,77 @@
+package stream
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReplayStream(t *testing.T) {
+	recording := strings.Join([]string{
+		`{"data":{"id":"1","created_at":"2022-01-01T00:00:00.000Z"}}`,
+		``,
+		`{"data":{"id":"2"}}`,
+		`{"data":{"id":"3","created_at":"2022-01-01T00:00:00.200Z"}}`,
+		`{"id":"4","created_at":"2022-01-01T00:00:00.100Z"}`,
+	}, "\r\n")
+
+	replay := func(t *testing.T, opts ...Option) ([]StreamMessage, time.Duration) {
+		instance := NewReplayStream(strings.NewReader(recording), opts...)
+		instance.SetUnmarshalHook(func(b []byte) (interface{}, error) {
+			return string(b), nil
+		})
+		start := time.Now()
+		messages := drain(t, instance)
+		return messages, time.Since(start)
+	}
+
+	t.Run("as fast as possible by default", func(t *testing.T) {
+		messages, elapsed := replay(t)
+
+		if len(messages) != 5 || messages[4].Err != io.EOF {
+			t.Fatalf("got %+v, want 4 messages and io.EOF", messages)
+		}
+		if messages[1].Data != `{"data":{"id":"2"}}` || messages[3].Data != `{"id":"4","created_at":"2022-01-01T00:00:00.100Z"}` {
+			t.Errorf("got %+v, want the recorded lines", messages)
+		}
+		if elapsed > 100*time.Millisecond {
+			t.Errorf("got %v, want no waiting", elapsed)
+		}
+	})
+
+	t.Run("paced by created_at", func(t *testing.T) {
+		// 200ms between the first and third tweets, replayed twice as fast, and none back to the fourth
+		messages, elapsed := replay(t, WithReplaySpeed(2))
+
+		if len(messages) != 5 {
+			t.Fatalf("got %d messages, want 5", len(messages))
+		}
+		if elapsed < 100*time.Millisecond || elapsed > 190*time.Millisecond {
+			t.Errorf("got %v, want about 100ms", elapsed)
+		}
+	})
+
+	t.Run("stopping interrupts the wait", func(t *testing.T) {
+		instance := NewReplayStream(strings.NewReader(
+			`{"data":{"created_at":"2022-01-01T00:00:00Z"}}`+"\n"+`{"data":{"created_at":"2022-01-02T00:00:00Z"}}`+"\n"),
+			WithReplaySpeed(1))
+		if err := instance.StartStream(nil); err != nil {
+			t.Fatalf("got err when starting stream %v", err)
+		}
+		<-instance.GetMessages()
+
+		stopped := make(chan struct{})
+		go func() {
+			instance.StopStream()
+			for range instance.GetMessages() {
+			}
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-time.After(time.Second):
+			t.Fatal("the stream did not stop while waiting a day")
+		}
+	})
+}
@@ -0,0 +1,26 @@
+package stream
+
+import "sync/atomic"
+
+// ShutdownReport tells whether stopping the stream lost data, and how well the consumer kept up before.
+type ShutdownReport struct {
+	// Delivered counts the messages handed to the consumer since the stream started.
+	Delivered uint64
+	// Buffered counts the messages that were read, or waiting in the message buffer, when the stream was stopped,
+	// and were discarded instead of delivered.
+	Buffered uint64
+	// Dropped counts the messages discarded by OverflowDropNewest while the stream ran.
+	Dropped uint64
+}
+
+// StopWithReport stops the stream like StopStream, waits for the read and delivery goroutines to finish,
+// and reports what was delivered and what was lost. It ties into the counters of Stats.
+func (s *Stream) StopWithReport() ShutdownReport {
+	s.StopStream()
+	if atomic.LoadInt32(&s.started) == 1 {
+		s.waitFinished()
+	}
+
+	stats := s.Stats()
+	return ShutdownReport{Delivered: stats.Delivered, Buffered: stats.Discarded, Dropped: stats.Dropped}
+}
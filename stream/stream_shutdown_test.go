@@ -0,0 +1,40 @@
+package stream
+
+import (
+	"testing"
+	"time"
+
+	"dev.freespoke.com/twitter-stream/httpclient"
+)
+
+func TestStopWithReport(t *testing.T) {
+	instance := NewSyntheticStream(1000, func() []byte { return []byte(`{"data":{"id":"1"}}`) }, WithMessageBuffer(10))
+	if err := instance.StartStream(nil); err != nil {
+		t.Fatalf("got err when starting stream %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		<-instance.GetMessages()
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	report := instance.StopWithReport()
+
+	if report.Delivered != 3 {
+		t.Errorf("got %d delivered, want 3", report.Delivered)
+	}
+	if report.Buffered < 10 {
+		t.Errorf("got %d buffered, want at least the full message buffer", report.Buffered)
+	}
+	if report.Dropped != 0 {
+		t.Errorf("got %d dropped, want 0", report.Dropped)
+	}
+}
+
+func TestStopWithReportBeforeStarting(t *testing.T) {
+	instance := NewStream(httpclient.NewHttpClientMock("foobar"), NewStreamResponseBodyReader())
+
+	if report := instance.StopWithReport(); report != (ShutdownReport{}) {
+		t.Errorf("got %+v, want an empty report", report)
+	}
+}
@@ -0,0 +1,28 @@
+package stream
+
+import (
+	"os"
+	"os/signal"
+)
+
+// CloseOnSignals stops the stream when the process receives one of the given signals, for example
+// `CloseOnSignals(os.Interrupt, syscall.SIGTERM)`. Only the first call installs a handler; later calls are a no-op.
+//
+// This installs a signal handler with signal.Notify. While it is installed, those signals no longer terminate the
+// process, so your program must exit on its own once the messages channel closes. The handler is removed when
+// the stream stops.
+func (s *Stream) CloseOnSignals(signals ...os.Signal) {
+	s.signalOnce.Do(func() {
+		c := make(chan os.Signal, 1)
+		signal.Notify(c, signals...)
+
+		go func() {
+			defer signal.Stop(c)
+			select {
+			case <-c:
+				s.StopStream()
+			case <-s.done:
+			}
+		}()
+	})
+}
@@ -0,0 +1,38 @@
+//go:build !windows
+// +build !windows
+
+package stream
+
+import (
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"dev.freespoke.com/twitter-stream/httpclient"
+)
+
+func TestCloseOnSignalsStopsStream(t *testing.T) {
+	instance := NewStream(httpclient.NewHttpClientMock("foobar"), NewStreamResponseBodyReader()).(*Stream)
+	instance.CloseOnSignals(syscall.SIGUSR1)
+	instance.CloseOnSignals(syscall.SIGUSR1)
+
+	if err := syscall.Kill(os.Getpid(), syscall.SIGUSR1); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-instance.done:
+	case <-time.After(time.Second):
+		t.Error("expected stream to stop after signal")
+	}
+}
+
+func TestCloseOnSignalsIsNoopWhenStopped(t *testing.T) {
+	instance := NewStream(httpclient.NewHttpClientMock("foobar"), NewStreamResponseBodyReader()).(*Stream)
+	instance.StopStream()
+	instance.CloseOnSignals(syscall.SIGUSR2)
+
+	// a second StopStream must not panic
+	instance.StopStream()
+}
@@ -0,0 +1,239 @@
+package stream
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"dev.freespoke.com/twitter-stream/tweet"
+)
+
+type (
+	// Stats is a snapshot of the counters a Stream maintains.
+	Stats struct {
+		// TagHits counts the tweets read per matching rule tag. It is only populated with WithTagCounters.
+		TagHits map[string]uint64
+		// Delivered counts the messages handed to the consumer, through the messages channel or a callback.
+		Delivered uint64
+		// Dropped counts the messages discarded by the OverflowDropNewest policy.
+		Dropped uint64
+		// Discarded counts the messages that were read but not delivered because the stream was stopped.
+		Discarded uint64
+		// Duplicates counts the tweets skipped by WithDedup.
+		Duplicates uint64
+		// Sanitized counts the messages with invalid UTF-8 replaced by WithUTF8Sanitize.
+		Sanitized uint64
+		// DecodeErrors counts the messages the unmarshal hook returned an error for.
+		DecodeErrors uint64
+		// Reconnects counts the successful reconnects made by WithAutoReconnect.
+		Reconnects uint64
+		// RateLimited counts the connection attempts rejected with an *httpclient.RateLimitError. 429s retried by
+		// the http client itself are not seen by the stream, see httpclient.WithRateLimitRetries.
+		RateLimited uint64
+		// Bytes counts the bytes read from Twitter across every connection, after the transport decompressed them.
+		Bytes uint64
+		// BytesPerSecond is the average rate bytes were read at over the last 10 whole seconds.
+		BytesPerSecond float64
+		// BufferedBytes is the size of the messages in the message buffer, not yet received by the consumer.
+		// See WithMaxBufferedBytes.
+		BufferedBytes uint64
+	}
+
+	// streamStats holds the live counters behind Stats. It is safe for concurrent use.
+	streamStats struct {
+		mu           sync.Mutex
+		tagHits      map[string]uint64
+		lastMatch    map[string]time.Time
+		delivered    uint64
+		dropped      uint64
+		discarded    uint64
+		duplicates   uint64
+		sanitized    uint64
+		decodeErrors uint64
+		reconnects   uint64
+		rateLimited  uint64
+		bytes        uint64
+		bandwidth    bandwidth
+		// connected, lastMessageAt and lastError are exposed by Health
+		connected     bool
+		lastMessageAt time.Time
+		lastError     string
+		bufferedBytes int
+	}
+)
+
+// WithTagCounters counts how many tweets matched each rule tag, exposed as Stats().TagHits.
+// Counting decodes the "matching_rules" of every message on the read goroutine, so it is opt-in.
+func WithTagCounters() Option {
+	return func(s *Stream) {
+		s.countTags = true
+	}
+}
+
+// Stats returns a snapshot of the stream's counters.
+func (s *Stream) Stats() Stats {
+	return s.stats.snapshot()
+}
+
+// countTags increments the hit counter of every rule tag that matched the message.
+func (st *streamStats) countTags(b []byte) {
+	matched := matchingRules(b)
+	if len(matched) == 0 {
+		return
+	}
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if st.tagHits == nil {
+		st.tagHits = make(map[string]uint64)
+		st.lastMatch = make(map[string]time.Time)
+	}
+	now := time.Now()
+	for _, rule := range matched {
+		st.tagHits[rule.Tag]++
+		st.lastMatch[rule.Tag] = now
+	}
+}
+
+// matchingRules decodes the "matching_rules" of a message, or returns nil if it has none.
+func matchingRules(b []byte) []tweet.MatchingRule {
+	message := struct {
+		MatchingRules []tweet.MatchingRule `json:"matching_rules"`
+	}{}
+	if err := json.Unmarshal(b, &message); err != nil {
+		return nil
+	}
+	return message.MatchingRules
+}
+
+// UnmatchedRules returns the tags of rules that matched no tweet since `since`, sorted, to find rules to prune.
+// It needs WithTagCounters. The stream only learns about a rule when a tweet matches it, so rules that never
+// matched at all are only reported when they are managed with WithManagedRules.
+func (s *Stream) UnmatchedRules(since time.Time) []string {
+	known := make(map[string]bool)
+	if s.managedRules != nil {
+		for _, rule := range s.managedRules.Add {
+			if rule != nil && rule.Tag != nil {
+				known[*rule.Tag] = true
+			}
+		}
+	}
+
+	s.stats.mu.Lock()
+	for tag := range s.stats.lastMatch {
+		known[tag] = true
+	}
+
+	unmatched := []string{}
+	for tag := range known {
+		if last, ok := s.stats.lastMatch[tag]; !ok || last.Before(since) {
+			unmatched = append(unmatched, tag)
+		}
+	}
+	s.stats.mu.Unlock()
+
+	sort.Strings(unmatched)
+	return unmatched
+}
+
+// addDelivered counts a message handed to the consumer.
+func (st *streamStats) addDelivered() {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.delivered++
+}
+
+// reserveBuffered counts `n` more bytes in the message buffer, unless that would exceed `max` bytes, which
+// is only allowed for the first message of an empty buffer. A `max` of 0 doesn't limit.
+func (st *streamStats) reserveBuffered(n int, max int) bool {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if max > 0 && st.bufferedBytes > 0 && st.bufferedBytes+n > max {
+		return false
+	}
+	st.bufferedBytes += n
+	return true
+}
+
+// releaseBuffered counts `n` bytes leaving the message buffer.
+func (st *streamStats) releaseBuffered(n int) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.bufferedBytes -= n
+}
+
+// addDiscarded counts a message that was not delivered because the stream was stopped.
+func (st *streamStats) addDiscarded() {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.discarded++
+}
+
+// addDecodeError counts a message the unmarshal hook failed to decode.
+func (st *streamStats) addDecodeError() {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.decodeErrors++
+}
+
+// addReconnect counts a successful reconnect.
+func (st *streamStats) addReconnect() {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.reconnects++
+}
+
+// addRateLimited counts a connection attempt rejected for rate limiting.
+func (st *streamStats) addRateLimited() {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.rateLimited++
+}
+
+// addDropped counts a message discarded by the overflow policy.
+func (st *streamStats) addDropped() {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.dropped++
+}
+
+// addDuplicate counts a tweet skipped by WithDedup.
+func (st *streamStats) addDuplicate() {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.duplicates++
+}
+
+// addSanitized counts a message whose invalid UTF-8 was replaced.
+func (st *streamStats) addSanitized() {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.sanitized++
+}
+
+func (st *streamStats) snapshot() Stats {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	stats := Stats{
+		Delivered:      st.delivered,
+		Dropped:        st.dropped,
+		Discarded:      st.discarded,
+		Duplicates:     st.duplicates,
+		Sanitized:      st.sanitized,
+		DecodeErrors:   st.decodeErrors,
+		Reconnects:     st.reconnects,
+		RateLimited:    st.rateLimited,
+		Bytes:          st.bytes,
+		BytesPerSecond: st.bandwidth.rate(time.Now()),
+		BufferedBytes:  uint64(st.bufferedBytes),
+	}
+	if st.tagHits != nil {
+		stats.TagHits = make(map[string]uint64, len(st.tagHits))
+		for tag, hits := range st.tagHits {
+			stats.TagHits[tag] = hits
+		}
+	}
+	return stats
+}
@@ -0,0 +1,124 @@
+package stream
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"dev.freespoke.com/twitter-stream/httpclient"
+	"dev.freespoke.com/twitter-stream/rules"
+)
+
+func TestTagCounters(t *testing.T) {
+	body := "{\"data\":{\"id\":\"1\"},\"matching_rules\":[{\"id\":\"1\",\"tag\":\"cats\"},{\"id\":\"2\",\"tag\":\"pets\"}]}\r\n" +
+		"{\"data\":{\"id\":\"2\"},\"matching_rules\":[{\"id\":\"1\",\"tag\":\"cats\"}]}\r\n"
+
+	instance := NewStream(givenStreamClient(body), NewStreamResponseBodyReader(), WithTagCounters())
+	drain(t, instance)
+
+	hits := instance.Stats().TagHits
+	if hits["cats"] != 2 || hits["pets"] != 1 {
+		t.Errorf("got %v, want cats:2 pets:1", hits)
+	}
+}
+
+func TestTagCountersAreOptIn(t *testing.T) {
+	body := "{\"data\":{\"id\":\"1\"},\"matching_rules\":[{\"id\":\"1\",\"tag\":\"cats\"}]}\r\n"
+
+	instance := NewStream(givenStreamClient(body), NewStreamResponseBodyReader())
+	drain(t, instance)
+
+	if instance.Stats().TagHits != nil {
+		t.Errorf("got %v, want nil", instance.Stats().TagHits)
+	}
+}
+
+func TestUnmatchedRules(t *testing.T) {
+	body := "{\"data\":{\"id\":\"1\"},\"matching_rules\":[{\"id\":\"1\",\"tag\":\"cats\"}]}\r\n"
+	desired := rules.NewRuleBuilder().AddRule("cat", "cats").AddRule("dog", "dogs").Build()
+
+	instance := NewStream(givenStreamClient(body), NewStreamResponseBodyReader(), WithTagCounters()).(*Stream)
+
+	before := time.Now()
+	drain(t, instance)
+	// set after the stream ran, so the rules aren't applied to the mock client
+	instance.managedRules = &desired
+
+	if unmatched := instance.UnmatchedRules(before); len(unmatched) != 1 || unmatched[0] != "dogs" {
+		t.Errorf("got %v, want [dogs]", unmatched)
+	}
+
+	if unmatched := instance.UnmatchedRules(time.Now().Add(time.Minute)); len(unmatched) != 2 {
+		t.Errorf("got %v, want [cats dogs]", unmatched)
+	}
+}
+
+func TestBytesAreCounted(t *testing.T) {
+	body := "{\"data\":{\"id\":\"1\"}}\r\n\r\n"
+	instance := NewStream(givenStreamClient(body), NewStreamResponseBodyReader())
+
+	drain(t, instance)
+
+	if got := instance.Stats().Bytes; got != uint64(len(body)) {
+		t.Errorf("got %d bytes, want %d", got, len(body))
+	}
+}
+
+func TestBandwidthRate(t *testing.T) {
+	var b bandwidth
+	start := time.Unix(1000, 0)
+	for i := 0; i < bandwidthWindow+5; i++ {
+		b.add(100, start.Add(time.Duration(i)*time.Second))
+	}
+	now := start.Add((bandwidthWindow + 4) * time.Second)
+	b.add(1000, now)
+
+	if got := b.rate(now); got != 100 {
+		t.Errorf("got %v bytes per second, want 100, ignoring the current second and ones outside the window", got)
+	}
+
+	if got := b.rate(now.Add(time.Hour)); got != 0 {
+		t.Errorf("got %v bytes per second, want 0 once idle", got)
+	}
+}
+
+func TestConnectionAndDecodeCounters(t *testing.T) {
+	responses := []error{nil, nil, &httpclient.RateLimitError{}, httpclient.ErrConnectionLimit}
+	mockClient := httpclient.NewHttpClientMock("foobar")
+	mockClient.MockGetSearchStream = func(queryParams *url.Values) (*http.Response, error) {
+		err := responses[0]
+		responses = responses[1:]
+		if err != nil {
+			return nil, err
+		}
+		body := fmt.Sprintf("%d\r\n", len(responses))
+		return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(body))}, nil
+	}
+
+	instance := NewStream(mockClient, NewStreamResponseBodyReader(), WithAutoReconnect()).(*Stream)
+	instance.backoff = BackoffFunc(func(attempt int) time.Duration { return 0 })
+	instance.SetUnmarshalHook(func(b []byte) (interface{}, error) {
+		if string(b) == "2" {
+			return nil, errors.New("bad message")
+		}
+		return string(b), nil
+	})
+
+	drain(t, instance)
+	stats := instance.Stats()
+
+	if stats.Reconnects != 1 {
+		t.Errorf("got %d reconnects, want 1", stats.Reconnects)
+	}
+	if stats.DecodeErrors != 1 {
+		t.Errorf("got %d decode errors, want 1", stats.DecodeErrors)
+	}
+	if stats.RateLimited != 1 {
+		t.Errorf("got %d rate limited, want 1", stats.RateLimited)
+	}
+}
@@ -0,0 +1,85 @@
+package stream
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"dev.freespoke.com/twitter-stream/httpclient"
+)
+
+// errSyntheticClient is returned by every request of a synthetic stream's client other than the stream itself.
+var errSyntheticClient = errors.New("synthetic streams only support streaming")
+
+// NewSyntheticStream creates a stream that delivers payloads made by `generator` at `rate` messages per second,
+// without any network. Payloads go through the same reader, unmarshal hook, and delivery path as a real stream,
+// so it is useful to load test a consumer or benchmark the library. A rate of 0 or less generates payloads as
+// fast as they are read. The stream runs until StopStream is called.
+func NewSyntheticStream(rate int, generator func() []byte, opts ...Option) IStream {
+	return NewStream(&syntheticClient{rate: rate, generator: generator}, NewStreamResponseBodyReader(), opts...)
+}
+
+// syntheticClient is an http client whose stream responses are generated.
+type syntheticClient struct {
+	rate      int
+	generator func() []byte
+}
+
+func (c *syntheticClient) GetSearchStream(queryParams *url.Values) (*http.Response, error) {
+	body := &syntheticBody{generator: c.generator, closed: make(chan struct{})}
+	if c.rate > 0 {
+		body.bucket = &tokenBucket{interval: time.Second / time.Duration(c.rate)}
+	}
+	return &http.Response{StatusCode: http.StatusOK, Body: body}, nil
+}
+
+func (c *syntheticClient) NewHttpRequest(opts *httpclient.RequestOpts) (*http.Response, error) {
+	return nil, errSyntheticClient
+}
+
+func (c *syntheticClient) GetRules() (*http.Response, error) {
+	return nil, errSyntheticClient
+}
+
+func (c *syntheticClient) AddRules(queryParams *url.Values, body string) (*http.Response, error) {
+	return nil, errSyntheticClient
+}
+
+func (c *syntheticClient) GenerateUrl(name string, queryParams *url.Values) (string, error) {
+	return "", errSyntheticClient
+}
+
+// syntheticBody is a response body that generates a message, terminated by "\r\n", whenever the bucket allows.
+type syntheticBody struct {
+	generator func() []byte
+	bucket    *tokenBucket
+	pending   []byte
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func (b *syntheticBody) Read(p []byte) (int, error) {
+	if len(b.pending) == 0 {
+		if b.bucket != nil {
+			b.bucket.wait(b.closed)
+		}
+		if stopped(b.closed) {
+			return 0, io.EOF
+		}
+		b.pending = append(append(b.pending, b.generator()...), '\r', '\n')
+	}
+
+	n := copy(p, b.pending)
+	b.pending = b.pending[n:]
+	return n, nil
+}
+
+func (b *syntheticBody) Close() error {
+	b.closeOnce.Do(func() {
+		close(b.closed)
+	})
+	return nil
+}
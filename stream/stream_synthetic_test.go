@@ -0,0 +1,52 @@
+package stream
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSyntheticStreamGeneratesAtRate(t *testing.T) {
+	instance := NewSyntheticStream(200, func() []byte {
+		return []byte(`{"data":{"id":"1","text":"synthetic"}}`)
+	})
+	instance.SetUnmarshalHook(func(b []byte) (interface{}, error) {
+		return string(b), nil
+	})
+
+	if err := instance.StartStream(nil); err != nil {
+		t.Fatalf("got err when starting stream %v", err)
+	}
+
+	start := time.Now()
+	for i := 0; i < 10; i++ {
+		message := <-instance.GetMessages()
+		if message.Err != nil || message.Data != `{"data":{"id":"1","text":"synthetic"}}` {
+			t.Fatalf("got %v, want the generated payload", message)
+		}
+	}
+
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("got 10 messages in %v, want at least 40ms at 200 per second", elapsed)
+	}
+
+	instance.StopStream()
+	for message := range instance.GetMessages() {
+		if message.Err != nil {
+			t.Errorf("got %v, want no error after StopStream", message.Err)
+		}
+	}
+}
+
+func BenchmarkSyntheticStream(b *testing.B) {
+	payload := []byte(`{"data":{"id":"1","text":"synthetic"}}`)
+	instance := NewSyntheticStream(0, func() []byte { return payload })
+	if err := instance.StartStream(nil); err != nil {
+		b.Fatal(err)
+	}
+	defer instance.StopStream()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		<-instance.GetMessages()
+	}
+}
@@ -0,0 +1,151 @@
+package stream
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// untaggedArchive is the file name used by WithTagArchive for rules without a tag.
+const untaggedArchive = "untagged"
+
+// TagArchiveOptions configures the rotation of the files written by WithTagArchive.
+// A file is rotated before a write would make it larger than MaxBytes, or once it has been written to for MaxAge.
+// Zero values don't rotate.
+type TagArchiveOptions struct {
+	MaxBytes int64
+	MaxAge   time.Duration
+}
+
+// tagArchive writes every tweet to a file per matching rule tag. It is only used by the read goroutine.
+type tagArchive struct {
+	dir     string
+	opts    TagArchiveOptions
+	files   map[string]*archiveFile
+	scratch []byte
+	now     func() time.Time
+}
+
+// archiveFile is the file a tag is currently written to.
+type archiveFile struct {
+	file     *os.File
+	size     int64
+	openedAt time.Time
+}
+
+// WithTagArchive writes every tweet, as read, to `<tag>.ndjson` in `dir` for each tag of its "matching_rules",
+// one tweet per line, so a tweet matching several rules is written to each of their files. Tags are made safe for
+// file names by replacing characters other than letters, digits, '.', '-' and '_' with '_', and rules without a tag
+// are written to `untagged.ndjson`. A file is appended to if it already exists, and is rotated as set by `opts`
+// by renaming it to `<tag>.<UTC time of the rotation>.ndjson`. The directory is created if needed.
+// The file of every tag stays open until the stream stops. Writes happen on the read goroutine, and errors are
+// logged and don't stop the stream.
+func WithTagArchive(dir string, opts TagArchiveOptions) Option {
+	return func(s *Stream) {
+		s.tagArchive = &tagArchive{dir: dir, opts: opts, files: make(map[string]*archiveFile), now: time.Now}
+	}
+}
+
+// write writes a message to the file of each of its tags.
+func (a *tagArchive) write(b []byte) {
+	a.scratch = append(append(a.scratch[:0], b...), '\n')
+	written := make(map[string]bool)
+	for _, rule := range matchingRules(b) {
+		name := archiveName(rule.Tag)
+		if written[name] {
+			continue
+		}
+		written[name] = true
+
+		if err := a.writeTo(name, a.scratch); err != nil {
+			log.Printf("Failed to archive a tweet of tag %q: %v", rule.Tag, err)
+		}
+	}
+}
+
+// writeTo appends `line` to the file `name`, rotating it first if needed.
+func (a *tagArchive) writeTo(name string, line []byte) error {
+	f := a.files[name]
+	if f != nil && a.due(f, len(line)) {
+		delete(a.files, name)
+		if err := a.rotate(name, f); err != nil {
+			return err
+		}
+		f = nil
+	}
+
+	if f == nil {
+		var err error
+		if f, err = a.open(name); err != nil {
+			return err
+		}
+		a.files[name] = f
+	}
+
+	n, err := f.file.Write(line)
+	f.size += int64(n)
+	return err
+}
+
+// due tells whether the file must be rotated before `n` more bytes are written to it.
+func (a *tagArchive) due(f *archiveFile, n int) bool {
+	if a.opts.MaxBytes > 0 && f.size > 0 && f.size+int64(n) > a.opts.MaxBytes {
+		return true
+	}
+	return a.opts.MaxAge > 0 && a.now().Sub(f.openedAt) >= a.opts.MaxAge
+}
+
+// open opens the file `name` for appending.
+func (a *tagArchive) open(name string) (*archiveFile, error) {
+	if err := os.MkdirAll(a.dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(filepath.Join(a.dir, name+".ndjson"), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &archiveFile{file: file, size: info.Size(), openedAt: a.now()}, nil
+}
+
+// rotate closes the file `name` and renames it with the time of the rotation.
+func (a *tagArchive) rotate(name string, f *archiveFile) error {
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+	rotated := fmt.Sprintf("%s.%s.ndjson", name, a.now().UTC().Format("20060102T150405.000000000"))
+	return os.Rename(f.file.Name(), filepath.Join(a.dir, rotated))
+}
+
+// close closes the file of every tag.
+func (a *tagArchive) close() {
+	for name, f := range a.files {
+		if err := f.file.Close(); err != nil {
+			log.Printf("Failed to close the archive of %q: %v", name, err)
+		}
+	}
+	a.files = make(map[string]*archiveFile)
+}
+
+// archiveName returns the file name, without extension, of the archive of `tag`.
+func archiveName(tag string) string {
+	if tag == "" {
+		return untaggedArchive
+	}
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, tag)
+}
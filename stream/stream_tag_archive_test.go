@@ -0,0 +1,79 @@
+package stream
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+)
+
+func readArchive(t *testing.T, dir string) map[string]string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := make(map[string]string)
+	for _, entry := range entries {
+		b, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[entry.Name()] = string(b)
+	}
+	return files
+}
+
+func TestTagArchive(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "archive")
+	one := `{"data":{"id":"1"},"matching_rules":[{"id":"1","tag":"cats"},{"id":"2","tag":"dog/pets"}]}`
+	two := `{"data":{"id":"2"},"matching_rules":[{"id":"1","tag":"cats"},{"id":"3","tag":""}]}`
+	body := one + "\r\n\r\n" + two + "\r\n"
+
+	drain(t, NewStream(givenStreamClient(body), NewStreamResponseBodyReader(), WithTagArchive(dir, TagArchiveOptions{})))
+
+	files := readArchive(t, dir)
+	if len(files) != 3 || files["cats.ndjson"] != one+"\n"+two+"\n" || files["dog_pets.ndjson"] != one+"\n" ||
+		files["untagged.ndjson"] != two+"\n" {
+		t.Errorf("got %v, want a file per tag", files)
+	}
+}
+
+func TestTagArchiveRotates(t *testing.T) {
+	one := `{"data":{"id":"1"},"matching_rules":[{"id":"1","tag":"cats"}]}`
+	body := one + "\r\n" + one + "\r\n" + one + "\r\n"
+
+	t.Run("by size", func(t *testing.T) {
+		dir := t.TempDir()
+
+		drain(t, NewStream(givenStreamClient(body), NewStreamResponseBodyReader(),
+			WithTagArchive(dir, TagArchiveOptions{MaxBytes: int64(2 * (len(one) + 1))})))
+
+		files := readArchive(t, dir)
+		var names []string
+		for name := range files {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		if len(files) != 2 || files["cats.ndjson"] != one+"\n" || files[names[0]] != one+"\n"+one+"\n" {
+			t.Errorf("got %v, want two tweets rotated and one in the current file", files)
+		}
+	})
+
+	t.Run("by age", func(t *testing.T) {
+		dir := t.TempDir()
+		instance := NewStream(givenStreamClient(body), NewStreamResponseBodyReader(),
+			WithTagArchive(dir, TagArchiveOptions{MaxAge: time.Minute})).(*Stream)
+		now := time.Now()
+		instance.tagArchive.now = func() time.Time {
+			now = now.Add(time.Minute)
+			return now
+		}
+
+		drain(t, instance)
+
+		if files := readArchive(t, dir); len(files) != 3 || files["cats.ndjson"] != one+"\n" {
+			t.Errorf("got %v, want a file per tweet", files)
+		}
+	})
+}
@@ -0,0 +1,27 @@
+package stream
+
+// WithTagFilter only delivers tweets that matched a rule with one of `tags`. Rules belong to the app, not to a
+// connection, so every connection of the app receives the tweets of every rule; this keeps the tweets of the
+// other rules out of this stream. Skipped tweets never reach the unmarshal hook or take a sequence number.
+// Finding the tags decodes every message on the read goroutine. Without tags, nothing is filtered.
+func WithTagFilter(tags ...string) Option {
+	return func(s *Stream) {
+		if len(tags) == 0 {
+			return
+		}
+		s.tagFilter = make(map[string]bool, len(tags))
+		for _, tag := range tags {
+			s.tagFilter[tag] = true
+		}
+	}
+}
+
+// matchesTagFilter tells whether the message matched a rule with one of the filtered tags.
+func (s *Stream) matchesTagFilter(b []byte) bool {
+	for _, rule := range matchingRules(b) {
+		if s.tagFilter[rule.Tag] {
+			return true
+		}
+	}
+	return false
+}
@@ -2,16 +2,41 @@ package stream
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
 	"testing"
+	"time"
 
 	"dev.freespoke.com/twitter-stream/httpclient"
 )
 
+func givenStreamClient(body string) httpclient.IHttpClient {
+	mockClient := httpclient.NewHttpClientMock("foobar")
+	mockClient.MockGetSearchStream = func(queryParams *url.Values) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(bytes.NewReader([]byte(body))),
+		}, nil
+	}
+	return mockClient
+}
+
+func drain(t *testing.T, instance IStream) []StreamMessage {
+	if err := instance.StartStream(nil); err != nil {
+		t.Fatalf("got err when starting stream %v", err)
+	}
+
+	var messages []StreamMessage
+	for message := range instance.GetMessages() {
+		messages = append(messages, message)
+	}
+	return messages
+}
+
 func TestGetMessages(t *testing.T) {
 	client := httpclient.NewHttpClientMock("foobar")
 	reader := NewStreamResponseBodyReader()
@@ -103,9 +128,173 @@ func TestStartStream(t *testing.T) {
 			res, _ := r.Data.([]byte)
 
 			if string(expected) != string(res) {
-				t.Errorf("got %v, want %s", result, tt.result)
+				t.Errorf("got %v, want %v", res, tt.result.Data)
 			}
 		})
 
 	}
 }
+
+func TestStartStreamRetriesInitialConnect(t *testing.T) {
+	var tests = []struct {
+		retries       int
+		errs          []error
+		expectedCalls int
+		expectErr     bool
+	}{
+		{2, []error{errors.New("503"), errors.New("503")}, 3, false},
+		{1, []error{errors.New("503"), errors.New("503")}, 2, true},
+		{0, []error{errors.New("503")}, 1, true},
+		{3, []error{httpclient.ErrConnectionLimit}, 1, true},
+	}
+
+	for i, tt := range tests {
+		testName := fmt.Sprintf("TestStartStreamRetriesInitialConnect (%d)", i)
+
+		t.Run(testName, func(t *testing.T) {
+			calls := 0
+			mockClient := httpclient.NewHttpClientMock("foobar")
+			mockClient.MockGetSearchStream = func(queryParams *url.Values) (*http.Response, error) {
+				calls++
+				if calls <= len(tt.errs) {
+					return nil, tt.errs[calls-1]
+				}
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       ioutil.NopCloser(bytes.NewReader([]byte("hello\r\n"))),
+				}, nil
+			}
+
+			instance := NewStream(mockClient, NewStreamResponseBodyReader(), WithInitialConnectRetries(tt.retries)).(*Stream)
+			instance.backoff = BackoffFunc(func(attempt int) time.Duration { return 0 })
+
+			err := instance.StartStream(nil)
+
+			if calls != tt.expectedCalls {
+				t.Errorf("got %d calls, want %d", calls, tt.expectedCalls)
+			}
+
+			if (err != nil) != tt.expectErr {
+				t.Errorf("got err %v, want err %v", err, tt.expectErr)
+			}
+		})
+	}
+}
+
+func TestStopStreamInterruptsInitialConnectRetries(t *testing.T) {
+	mockClient := httpclient.NewHttpClientMock("foobar")
+	mockClient.MockGetSearchStream = func(queryParams *url.Values) (*http.Response, error) {
+		return nil, errors.New("503")
+	}
+
+	instance := NewStream(mockClient, NewStreamResponseBodyReader(), WithInitialConnectRetries(1)).(*Stream)
+	instance.backoff = BackoffFunc(func(attempt int) time.Duration { return time.Hour })
+
+	result := make(chan error, 1)
+	go func() {
+		result <- instance.StartStream(nil)
+	}()
+	instance.StopStream()
+
+	select {
+	case err := <-result:
+		if err == nil {
+			t.Errorf("got nil, want the connect error")
+		}
+	case <-time.After(time.Second):
+		t.Errorf("StartStream kept waiting after StopStream")
+	}
+}
+
+func TestExponentialBackoffStaysWithinBounds(t *testing.T) {
+	backoff := NewExponentialBackoff()
+	for attempt := 0; attempt < 100; attempt++ {
+		delay := backoff.NextDelay(attempt)
+		if delay <= 0 || delay > maxBackoff {
+			t.Errorf("got %v for attempt %d, want within (0, %v]", delay, attempt, maxBackoff)
+		}
+	}
+}
+
+func TestStreamMessagesAreSequenced(t *testing.T) {
+	mockClient := httpclient.NewHttpClientMock("foobar")
+	mockClient.MockGetSearchStream = func(queryParams *url.Values) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(bytes.NewReader([]byte("one\r\n\r\ntwo\r\nthree\r\n"))),
+		}, nil
+	}
+
+	instance := NewStream(mockClient, NewStreamResponseBodyReader())
+	if err := instance.StartStream(nil); err != nil {
+		t.Fatalf("got err when starting stream %v", err)
+	}
+
+	var expected uint64
+	for message := range instance.GetMessages() {
+		expected++
+		if message.Sequence != expected {
+			t.Errorf("got sequence %d, want %d", message.Sequence, expected)
+		}
+	}
+
+	// three messages and the terminal io.EOF error
+	if expected != 4 {
+		t.Errorf("got %d messages, want 4", expected)
+	}
+}
+
+func TestStreamMessagesAreStampedWhenReceived(t *testing.T) {
+	body := "{\"data\":{\"id\":\"1\"}}\r\n{\"warning\":{\"code\":\"connection_issue\"}}\r\n"
+	for _, workers := range []int{0, 2} {
+		before := time.Now()
+		instance := NewStream(givenStreamClient(body), NewStreamResponseBodyReader(), WithDecodeWorkers(workers))
+
+		messages := drain(t, instance)
+
+		previous := before
+		for _, message := range messages {
+			if message.ReceivedAt.Before(previous) || message.ReceivedAt.After(time.Now()) {
+				t.Errorf("got ReceivedAt %v for %+v with %d workers, want it after %v", message.ReceivedAt, message, workers, previous)
+			}
+			previous = message.ReceivedAt
+		}
+		if len(messages) != 3 {
+			t.Errorf("got %d messages with %d workers, want 3", len(messages), workers)
+		}
+	}
+}
+
+func TestStopStreamIsIdempotent(t *testing.T) {
+	instance := NewStream(httpclient.NewHttpClientMock("foobar"), NewStreamResponseBodyReader())
+
+	instance.StopStream()
+	instance.StopStream()
+}
+
+func TestStreamDeliversWarningsAsErr(t *testing.T) {
+	mockClient := httpclient.NewHttpClientMock("foobar")
+	mockClient.MockGetSearchStream = func(queryParams *url.Values) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(bytes.NewReader([]byte("{\"warning\":{\"code\":\"FALLING_BEHIND\",\"message\":\"slow\"}}\r\n{\"data\":{\"id\":\"1\"}}\r\n"))),
+		}, nil
+	}
+
+	instance := NewStream(mockClient, NewStreamResponseBodyReader())
+	if err := instance.StartStream(nil); err != nil {
+		t.Fatalf("got err when starting stream %v", err)
+	}
+
+	first := <-instance.GetMessages()
+	var warning *StreamWarning
+	if !errors.As(first.Err, &warning) || warning.Code != "FALLING_BEHIND" {
+		t.Errorf("got %v, want FALLING_BEHIND warning", first.Err)
+	}
+
+	second := <-instance.GetMessages()
+	if second.Err != nil || string(second.Data.([]byte)) != `{"data":{"id":"1"}}` {
+		t.Errorf("got %v, want tweet data", second)
+	}
+	instance.StopStream()
+}
@@ -0,0 +1,67 @@
+package stream
+
+import (
+	"errors"
+	"net/url"
+	"time"
+)
+
+// ErrTestConnectionTimeout is returned by TestConnection when no message arrived before the timeout.
+var ErrTestConnectionTimeout = errors.New("timed out waiting for the first stream message")
+
+// TestConnection is a smoke test that proves credentials, rules, and network all work end to end.
+// It opens a separate connection with this stream's client, unmarshal hook and `queryParams`, waits for the first
+// message that isn't a keep-alive, then disconnects. The connection is read with the same kind of reader as this
+// stream, and the options that change how a message is read or filtered, like WithMaxMessageSize,
+// WithUTF8Sanitize, WithLineProcessor, WithTagFilter and WithDataOnly, are applied to it too. Options with side
+// effects, like the sinks, archives and reconnects, are not. This stream is not started or changed, so it can
+// still be used afterwards.
+// The returned message may carry an Err, such as a StreamWarning, which is also returned as the error.
+// ErrTestConnectionTimeout is returned if nothing arrived within `timeout`, which includes the time to connect.
+func (s *Stream) TestConnection(queryParams *url.Values, timeout time.Duration) (*StreamMessage, error) {
+	probe := NewStream(s.client(), newReaderLike(s.reader), WithMaxMessageSize(s.maxMessageSize)).(*Stream)
+	probe.sanitizeUTF8 = s.sanitizeUTF8
+	probe.lineProcessor = s.lineProcessor
+	probe.tagFilter = s.tagFilter
+	probe.dataOnly = s.dataOnly
+	probe.SetUnmarshalHook(func(bytes []byte) (interface{}, error) {
+		// the probe keeps reading after the first message, so hand the hook a copy it can keep
+		return s.unmarshalHook(append([]byte(nil), bytes...))
+	})
+	defer probe.StopStream()
+
+	result := make(chan error, 1)
+	go func() {
+		result <- probe.StartStream(queryParams)
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case err := <-result:
+		if err != nil {
+			return nil, err
+		}
+	case <-timer.C:
+		return nil, ErrTestConnectionTimeout
+	}
+
+	select {
+	case message, ok := <-probe.messages:
+		if !ok {
+			return nil, ErrTestConnectionTimeout
+		}
+		return &message, message.Err
+	case <-timer.C:
+		return nil, ErrTestConnectionTimeout
+	}
+}
+
+// newReaderLike returns a new reader of the same kind as `reader`, which may already be reading another body.
+func newReaderLike(reader IStreamResponseBodyReader) IStreamResponseBodyReader {
+	if _, ok := reader.(*streamJSONDecoderReader); ok {
+		return NewStreamJSONDecoderReader()
+	}
+	return NewStreamResponseBodyReader()
+}
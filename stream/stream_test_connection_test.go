@@ -0,0 +1,71 @@
+package stream
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"dev.freespoke.com/twitter-stream/httpclient"
+)
+
+func TestTestConnectionReturnsFirstMessage(t *testing.T) {
+	instance := NewStream(givenStreamClient("\r\n\r\n{\"data\":{\"id\":\"1\"}}\r\n{\"data\":{\"id\":\"2\"}}\r\n"), NewStreamResponseBodyReader())
+
+	message, err := instance.TestConnection(nil, time.Second)
+
+	if err != nil {
+		t.Fatalf("got err %v", err)
+	}
+
+	if string(message.Data.([]byte)) != `{"data":{"id":"1"}}` {
+		t.Errorf("got %s, want the first tweet", message.Data)
+	}
+}
+
+func TestTestConnectionTimesOut(t *testing.T) {
+	reader, writer := io.Pipe()
+	defer writer.Close()
+
+	mockClient := httpclient.NewHttpClientMock("foobar")
+	mockClient.MockGetSearchStream = func(queryParams *url.Values) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Body: reader}, nil
+	}
+
+	instance := NewStream(mockClient, NewStreamResponseBodyReader())
+	message, err := instance.TestConnection(nil, 20*time.Millisecond)
+
+	if err != ErrTestConnectionTimeout {
+		t.Errorf("got err %v, want ErrTestConnectionTimeout", err)
+	}
+
+	if message != nil {
+		t.Errorf("got %v, want nil", message)
+	}
+}
+
+func TestTestConnectionUsesQueryParamsAndReader(t *testing.T) {
+	var query string
+	mockClient := httpclient.NewHttpClientMock("foobar")
+	mockClient.MockGetSearchStream = func(queryParams *url.Values) (*http.Response, error) {
+		query = queryParams.Encode()
+		// two values on one line can only be told apart by the json decoder reader
+		return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader("{\"data\":{\"id\":\"1\"}} {\"data\":{\"id\":\"2\"}}\r\n"))}, nil
+	}
+
+	instance := NewStream(mockClient, NewStreamJSONDecoderReader())
+	message, err := instance.TestConnection(NewStreamQueryParamsBuilder().AddTweetField("lang").Build(), time.Second)
+
+	if err != nil {
+		t.Fatalf("got err %v", err)
+	}
+	if query != "tweet.fields=lang" {
+		t.Errorf("got query %q, want the query params", query)
+	}
+	if string(message.Data.([]byte)) != `{"data":{"id":"1"}}` {
+		t.Errorf("got %s, want the first tweet read by the json decoder reader", message.Data)
+	}
+}
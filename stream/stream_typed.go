@@ -0,0 +1,111 @@
+package stream
+
+import (
+	"encoding/json"
+	"net/url"
+	"sync"
+	"time"
+
+	"dev.freespoke.com/twitter-stream/httpclient"
+	"dev.freespoke.com/twitter-stream/tweet"
+)
+
+type (
+	// TypedMessage is the message that is sent from a TypedStream's messages channel.
+	// Decode errors are delivered as Err, with Data left as its zero value.
+	// Sequence, ReceivedAt and Closed are those of the StreamMessage.
+	TypedMessage[T any] struct {
+		Data       T
+		Err        error
+		Sequence   uint64
+		ReceivedAt time.Time
+		Closed     bool
+	}
+
+	// TypedStream is a Stream that decodes every message into T with encoding/json before delivering it.
+	// It is a fully typed alternative to setting an unmarshal hook and asserting the type of StreamMessage.Data.
+	TypedStream[T any] struct {
+		stream   *Stream
+		messages chan TypedMessage[T]
+		done     chan struct{}
+		stopOnce sync.Once
+	}
+)
+
+// WithDisallowUnknownFields makes a TypedStream deliver a `tweet.ErrUnknownField` error, instead of the data, for every
+// message with a field that T doesn't decode, see `tweet.DisallowUnknownFields`. It is off by default, and is meant
+// for tests catching changes of the payload that the model doesn't capture yet. Other streams ignore it.
+func WithDisallowUnknownFields() Option {
+	return func(s *Stream) {
+		s.disallowUnknownFields = true
+	}
+}
+
+// NewTyped starts a stream with a bearer token and decodes every message into T, such as `tweet.StreamResponse`.
+// Query params are the same as `StartStream`.
+func NewTyped[T any](token string, queryParams *url.Values, opts ...Option) (*TypedStream[T], error) {
+	return NewTypedWithClient[T](httpclient.NewHttpClient(token), queryParams, opts...)
+}
+
+// NewTypedWithClient is NewTyped with an existing httpclient, e.g. one created with `httpclient.WithBaseURL`.
+func NewTypedWithClient[T any](httpClient httpclient.IHttpClient, queryParams *url.Values, opts ...Option) (*TypedStream[T], error) {
+	s := NewStream(httpClient, NewStreamResponseBodyReader(), opts...).(*Stream)
+	s.SetUnmarshalHook(func(bytes []byte) (interface{}, error) {
+		var data T
+		if err := json.Unmarshal(bytes, &data); err != nil {
+			// json.Unmarshal may have partly filled data before failing
+			var zero T
+			return zero, err
+		}
+		if s.disallowUnknownFields {
+			if err := tweet.CheckUnknownFields(bytes, &data); err != nil {
+				var zero T
+				return zero, err
+			}
+		}
+		return data, nil
+	})
+
+	if err := s.StartStream(queryParams); err != nil {
+		return nil, err
+	}
+
+	t := &TypedStream[T]{stream: s, messages: make(chan TypedMessage[T]), done: make(chan struct{})}
+	go t.convert()
+	return t, nil
+}
+
+// Messages returns the read-only typed messages channel. It is closed when the stream stops.
+func (t *TypedStream[T]) Messages() <-chan TypedMessage[T] {
+	return t.messages
+}
+
+// Stream returns the underlying Stream, for example to read its Stats.
+func (t *TypedStream[T]) Stream() IStream {
+	return t.stream
+}
+
+// StopStream stops the stream and closes the messages channel.
+func (t *TypedStream[T]) StopStream() {
+	t.stopOnce.Do(func() {
+		close(t.done)
+		t.stream.StopStream()
+	})
+}
+
+func (t *TypedStream[T]) convert() {
+	defer close(t.messages)
+	for message := range t.stream.GetMessages() {
+		data, _ := message.Data.(T)
+		select {
+		case t.messages <- TypedMessage[T]{
+			Data:       data,
+			Err:        message.Err,
+			Sequence:   message.Sequence,
+			ReceivedAt: message.ReceivedAt,
+			Closed:     message.Closed,
+		}:
+		case <-t.done:
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package stream
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"dev.freespoke.com/twitter-stream/tweet"
+)
+
+type typedTestTweet struct {
+	Data struct {
+		ID   string `json:"id"`
+		Text string `json:"text"`
+	} `json:"data"`
+}
+
+func TestTypedStreamDecodesMessages(t *testing.T) {
+	body := "{\"data\":{\"id\":\"1\",\"text\":\"hello\"}}\r\nnot json\r\n{\"data\":{\"id\":\"2\",\"text\":3}}\r\n"
+
+	instance, err := NewTypedWithClient[typedTestTweet](givenStreamClient(body), nil)
+	if err != nil {
+		t.Fatalf("got err when starting stream %v", err)
+	}
+
+	var messages []TypedMessage[typedTestTweet]
+	for message := range instance.Messages() {
+		messages = append(messages, message)
+	}
+
+	if len(messages) != 4 {
+		t.Fatalf("got %d messages, want 4", len(messages))
+	}
+
+	if messages[0].Err != nil || messages[0].Data.Data.Text != "hello" {
+		t.Errorf("got %+v, want decoded tweet", messages[0])
+	}
+
+	if messages[1].Err == nil || messages[1].Data.Data.ID != "" {
+		t.Errorf("got %+v, want decode error with zero data", messages[1])
+	}
+
+	// the id is decoded before the text fails to, but must not be delivered
+	if messages[2].Err == nil || messages[2].Data.Data.ID != "" {
+		t.Errorf("got %+v, want type mismatch error with zero data", messages[2])
+	}
+
+	if messages[3].Err != io.EOF || messages[3].Sequence != 4 {
+		t.Errorf("got %+v, want io.EOF as message 4", messages[3])
+	}
+}
+
+func TestTypedStreamStops(t *testing.T) {
+	body := "{\"data\":{\"id\":\"1\",\"text\":\"hello\"}}\r\n"
+
+	instance, err := NewTypedWithClient[typedTestTweet](givenStreamClient(body), nil)
+	if err != nil {
+		t.Fatalf("got err when starting stream %v", err)
+	}
+	instance.StopStream()
+
+	for range instance.Messages() {
+	}
+}
+
+func TestTypedStreamDisallowsUnknownFields(t *testing.T) {
+	body := "{\"data\":{\"id\":\"1\",\"text\":\"hello\"}}\r\n{\"data\":{\"id\":\"2\",\"text\":\"hello\",\"lang\":\"en\"}}\r\n"
+
+	instance, err := NewTypedWithClient[typedTestTweet](givenStreamClient(body), nil, WithDisallowUnknownFields())
+	if err != nil {
+		t.Fatalf("got err when starting stream %v", err)
+	}
+
+	var messages []TypedMessage[typedTestTweet]
+	for message := range instance.Messages() {
+		messages = append(messages, message)
+	}
+
+	if len(messages) != 3 {
+		t.Fatalf("got %d messages, want 3", len(messages))
+	}
+	if messages[0].Err != nil || messages[0].Data.Data.ID != "1" {
+		t.Errorf("got %+v, want decoded tweet", messages[0])
+	}
+	if !errors.Is(messages[1].Err, tweet.ErrUnknownField) || messages[1].Data.Data.ID != "" {
+		t.Errorf("got %+v, want %v with zero data", messages[1], tweet.ErrUnknownField)
+	}
+}
@@ -0,0 +1,28 @@
+package stream
+
+import (
+	"bytes"
+	"unicode/utf8"
+)
+
+// replacementChar is what invalid UTF-8 sequences are replaced with by WithUTF8Sanitize.
+var replacementChar = []byte(string(utf8.RuneError))
+
+// WithUTF8Sanitize replaces invalid UTF-8 sequences in every message with the replacement character U+FFFD
+// before it is passed to the unmarshal hook, so a single bad byte can't break JSON processing downstream.
+// Sanitized messages are counted in Stats().Sanitized. Validating costs a pass over every message, so it is opt-in.
+func WithUTF8Sanitize(sanitize bool) Option {
+	return func(s *Stream) {
+		s.sanitizeUTF8 = sanitize
+	}
+}
+
+// sanitize returns `b`, or a copy of it with invalid UTF-8 replaced.
+func (s *Stream) sanitize(b []byte) []byte {
+	if utf8.Valid(b) {
+		return b
+	}
+
+	s.stats.addSanitized()
+	return bytes.ToValidUTF8(b, replacementChar)
+}
@@ -0,0 +1,26 @@
+package stream
+
+import "testing"
+
+func TestUTF8Sanitize(t *testing.T) {
+	body := "{\"data\":{\"text\":\"caf\xe9\"}}\r\n{\"data\":{\"text\":\"café\"}}\r\n"
+
+	instance := NewStream(givenStreamClient(body), NewStreamResponseBodyReader(), WithUTF8Sanitize(true))
+	instance.SetUnmarshalHook(func(b []byte) (interface{}, error) {
+		return string(b), nil
+	})
+
+	messages := drain(t, instance)
+
+	if messages[0].Data != "{\"data\":{\"text\":\"caf�\"}}" {
+		t.Errorf("got %q, want the invalid byte replaced", messages[0].Data)
+	}
+
+	if messages[1].Data != "{\"data\":{\"text\":\"café\"}}" {
+		t.Errorf("got %q, want valid UTF-8 untouched", messages[1].Data)
+	}
+
+	if instance.Stats().Sanitized != 1 {
+		t.Errorf("got %d sanitized, want 1", instance.Stats().Sanitized)
+	}
+}
@@ -8,9 +8,21 @@ package stream
 import (
 	"bufio"
 	"bytes"
+	"errors"
 	"io"
+
+	"dev.freespoke.com/twitter-stream/httpclient"
 )
 
+// isFatal returns true if retrying the request that caused err can not succeed.
+// Rejected credentials are fatal unless WithRetryUnauthorized is used.
+func (s *Stream) isFatal(err error) bool {
+	if errors.Is(err, httpclient.ErrUnauthorized) {
+		return !s.retryUnauthorized
+	}
+	return errors.Is(err, httpclient.ErrConnectionLimit)
+}
+
 // stopped returns true if the done channel receives, false otherwise.
 func stopped(done <-chan struct{}) bool {
 	select {
@@ -31,8 +43,14 @@ type (
 	// streamResponseBodyReader is a buffered reader for Twitter stream response
 	// body. It can scan the arbitrary length of response body unlike bufio.Scanner.
 	streamResponseBodyReader struct {
-		reader *bufio.Reader
-		buf    bytes.Buffer
+		reader  *bufio.Reader
+		buf     bytes.Buffer
+		maxSize int
+	}
+
+	// messageSizeLimiter is implemented by readers that can limit the size of a message, see WithMaxMessageSize.
+	messageSizeLimiter interface {
+		setMaxMessageSize(n int)
 	}
 )
 
@@ -48,9 +66,15 @@ func (r *streamResponseBodyReader) setStreamResponseBody(body io.Reader) {
 	r.reader = bufio.NewReader(body)
 }
 
+// setMaxMessageSize makes readNext fail with ErrMessageTooLarge once a message is longer than `n` bytes.
+func (r *streamResponseBodyReader) setMaxMessageSize(n int) {
+	r.maxSize = n
+}
+
 // readNext reads Twitter stream response body and returns the next stream
 // content if exists. Returns io.EOF error if we reached the end of the stream
-// and there's no more message to read.
+// and there's no more message to read. A message split across many reads of the
+// body is accumulated until its delimiter is read.
 func (r *streamResponseBodyReader) readNext() ([]byte, error) {
 	// Discard all the bytes from buf and continue to use the allocated memory
 	// space for reading the next message.
@@ -62,7 +86,16 @@ func (r *streamResponseBodyReader) readNext() ([]byte, error) {
 		// first break out each line on '\n' and then check whether the line ends
 		// with "\r\n" to find message boundaries.
 		// https://dev.twitter.com/streaming/overview/processing
-		line, err := r.reader.ReadBytes('\n')
+		// ReadSlice returns at most a buffer of data, so a long message is read in
+		// parts, and only the parts read so far are held in memory.
+		line, err := r.reader.ReadSlice('\n')
+		if err == bufio.ErrBufferFull {
+			r.buf.Write(line)
+			if r.maxSize > 0 && r.buf.Len() > r.maxSize {
+				return nil, ErrMessageTooLarge
+			}
+			continue
+		}
 		// Non-EOF error should be propagated to callers immediately.
 		if err != nil && err != io.EOF {
 			return nil, err
@@ -80,16 +113,23 @@ func (r *streamResponseBodyReader) readNext() ([]byte, error) {
 			// Otherwise, we still have a remaining stream message to return.
 			break
 		}
+		r.buf.Write(line)
+		if r.maxSize > 0 && r.buf.Len() > r.maxSize {
+			return nil, ErrMessageTooLarge
+		}
 		// If the line ends with "\r\n", it's the end of one stream message data.
-		if bytes.HasSuffix(line, []byte("\r\n")) {
-			// reader.ReadBytes() returns a slice including the delimiter itself, so
-			// we need to trim '\n' as well as '\r' from the end of the slice.
-			r.buf.Write(bytes.TrimRight(line, "\r\n"))
+		// The '\r' may have been read with a previous part of the line, so buf is checked.
+		if bytes.HasSuffix(r.buf.Bytes(), []byte("\r\n")) {
+			// reader.ReadSlice() returns a slice including the delimiter itself, so
+			// we need to trim '\n' as well as '\r' from the end of the message.
+			r.buf.Truncate(len(bytes.TrimRight(r.buf.Bytes(), "\r\n")))
+			break
+		}
+		// Otherwise, the line is not the end of a stream message, so we keep
+		// the line in buf and continue to scan lines.
+		if err == io.EOF {
 			break
 		}
-		// Otherwise, the line is not the end of a stream message, so we append
-		// the line to buf and continue to scan lines.
-		r.buf.Write(line)
 	}
 
 	// Get the stream message bytes from buf. Not that Bytes() won't mark the
@@ -0,0 +1,63 @@
+package stream
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"dev.freespoke.com/twitter-stream/httpclient"
+)
+
+// givenOneByteStreamClient returns a stream client whose body returns a single byte per Read.
+func givenOneByteStreamClient(body string) httpclient.IHttpClient {
+	mockClient := httpclient.NewHttpClientMock("foobar")
+	mockClient.MockGetSearchStream = func(queryParams *url.Values) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(iotest.OneByteReader(strings.NewReader(body))),
+		}, nil
+	}
+	return mockClient
+}
+
+func TestReadNextAccumulatesPartialReads(t *testing.T) {
+	// longer than the buffer of the line reader, so it is read in several parts too
+	payload := `{"data":{"id":"1","text":"` + strings.Repeat("a", 5000) + `"}}`
+	stringHook := func(b []byte) (interface{}, error) { return string(b), nil }
+
+	readers := map[string]IStreamResponseBodyReader{
+		"line reader":         NewStreamResponseBodyReader(),
+		"json decoder reader": NewStreamJSONDecoderReader(),
+	}
+	for name, reader := range readers {
+		t.Run(name, func(t *testing.T) {
+			instance := NewStream(givenOneByteStreamClient("\r\n"+payload+"\r\n"), reader)
+			instance.SetUnmarshalHook(stringHook)
+
+			messages := drain(t, instance)
+
+			if len(messages) != 2 || messages[0].Err != nil || messages[0].Data != payload {
+				t.Fatalf("got %d messages, first %+v, want the payload then the end of the stream", len(messages), messages[0].Err)
+			}
+			if messages[1].Err != io.EOF {
+				t.Errorf("got err %v, want %v", messages[1].Err, io.EOF)
+			}
+		})
+	}
+}
+
+func TestMaxMessageSize(t *testing.T) {
+	body := "{\"data\":{\"id\":\"1\"}}\r\n" + strings.Repeat("a", 10000)
+	instance := NewStream(givenStreamClient(body), NewStreamResponseBodyReader(), WithMaxMessageSize(100))
+
+	messages := drain(t, instance)
+
+	if len(messages) != 2 || messages[0].Err != nil || !errors.Is(messages[1].Err, ErrMessageTooLarge) {
+		t.Errorf("got %+v, want the first message then %v", messages, ErrMessageTooLarge)
+	}
+}
@@ -0,0 +1,106 @@
+package stream
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+type (
+	// StreamWarning is an operational message from Twitter about the health of the connection, such as a
+	// `connection_issue` or a `reset`. It is delivered as the Err of a StreamMessage instead of being passed to
+	// the unmarshal hook. A warning means the connection is at risk, not that it has dropped, so you can keep
+	// reading after logging it:
+	//
+	//	var warning *stream.StreamWarning
+	//	if errors.As(message.Err, &warning) {
+	//		log.Printf("twitter warned us: %v", warning)
+	//		continue
+	//	}
+	//
+	// Code is the warning type sent by Twitter. Unknown warning types are passed through with whatever code
+	// Twitter sent. When the message is an `errors` array, Errors holds every entry, so you can branch on
+	// their Title or Type. Raw holds a copy of the original message.
+	StreamWarning struct {
+		Code    string
+		Message string
+		Errors  []StreamErrorPayload
+		Raw     []byte
+	}
+
+	// StreamErrorPayload is an entry of the top-level `errors` array Twitter sends on the stream,
+	// for example {"title":"operational-disconnect","disconnect_type":"UpstreamOperationalDisconnect"}.
+	StreamErrorPayload struct {
+		Title           string `json:"title"`
+		Detail          string `json:"detail"`
+		Type            string `json:"type"`
+		ConnectionIssue string `json:"connection_issue"`
+		DisconnectType  string `json:"disconnect_type"`
+	}
+
+	// operationalFrame is the shape of stream messages that carry warnings instead of tweets.
+	operationalFrame struct {
+		Data    json.RawMessage `json:"data"`
+		Warning *struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"warning"`
+		Errors []StreamErrorPayload `json:"errors"`
+	}
+)
+
+// Error implements the error interface.
+func (w *StreamWarning) Error() string {
+	return fmt.Sprintf("twitter stream warning %s: %s", w.Code, w.Message)
+}
+
+// parseWarning returns a StreamWarning if the message is an operational warning rather than a tweet.
+func parseWarning(b []byte) *StreamWarning {
+	if bytes.HasPrefix(b, []byte(`{"data"`)) {
+		return nil
+	}
+
+	frame := operationalFrame{}
+	if err := json.Unmarshal(b, &frame); err != nil || len(frame.Data) > 0 {
+		return nil
+	}
+
+	raw := append([]byte(nil), b...)
+	if frame.Warning != nil {
+		return &StreamWarning{Code: frame.Warning.Code, Message: frame.Warning.Message, Raw: raw}
+	}
+
+	if len(frame.Errors) == 0 {
+		return nil
+	}
+
+	warning := &StreamWarning{Errors: frame.Errors, Raw: raw}
+	for _, e := range frame.Errors {
+		if code := e.code(); code != "" && warning.Code == "" {
+			warning.Code = code
+			warning.Message = e.message()
+		}
+	}
+	if warning.Code == "" {
+		warning.Code = frame.Errors[0].Title
+		warning.Message = frame.Errors[0].message()
+	}
+
+	return warning
+}
+
+// code returns the connection issue or disconnect type of the error, if it has one.
+func (e StreamErrorPayload) code() string {
+	if e.ConnectionIssue != "" {
+		return e.ConnectionIssue
+	}
+	return e.DisconnectType
+}
+
+// message returns the detail of the error, or its title if there is no detail.
+func (e StreamErrorPayload) message() string {
+	if e.Detail != "" {
+		return e.Detail
+	}
+	return e.Title
+}
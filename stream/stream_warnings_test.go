@@ -0,0 +1,79 @@
+package stream
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestParseWarning(t *testing.T) {
+	var tests = []struct {
+		message string
+		result  *StreamWarning
+	}{
+		{`{"data":{"id":"1","text":"hello"}}`, nil},
+		{`not json`, nil},
+		{
+			`{"errors":[{"title":"Invalid Request","detail":"bad param","type":"https://api.twitter.com/2/problems/invalid-request"}]}`,
+			&StreamWarning{Code: "Invalid Request", Message: "bad param"},
+		},
+		{
+			`{"errors":[{"title":"ConnectionException","detail":"This stream is currently at the maximum allowed connection limit.","connection_issue":"TooManyConnections"}]}`,
+			&StreamWarning{Code: "TooManyConnections", Message: "This stream is currently at the maximum allowed connection limit."},
+		},
+		{
+			`{"errors":[{"title":"operational-disconnect","disconnect_type":"reset"}]}`,
+			&StreamWarning{Code: "reset", Message: "operational-disconnect"},
+		},
+		{
+			`{"warning":{"code":"SOMETHING_NEW","message":"a warning we don't know yet"}}`,
+			&StreamWarning{Code: "SOMETHING_NEW", Message: "a warning we don't know yet"},
+		},
+	}
+
+	for i, tt := range tests {
+		testName := fmt.Sprintf("TestParseWarning (%d)", i)
+
+		t.Run(testName, func(t *testing.T) {
+			result := parseWarning([]byte(tt.message))
+
+			if tt.result == nil {
+				if result != nil {
+					t.Errorf("got %v, want nil", result)
+				}
+				return
+			}
+
+			if result == nil {
+				t.Fatalf("got nil, want %v", tt.result)
+			}
+
+			if result.Code != tt.result.Code || result.Message != tt.result.Message {
+				t.Errorf("got %v, want %v", result, tt.result)
+			}
+
+			if string(result.Raw) != tt.message {
+				t.Errorf("got %s, want %s", string(result.Raw), tt.message)
+			}
+		})
+	}
+}
+
+func TestParseWarningDecodesErrorsPayload(t *testing.T) {
+	message := `{"errors":[{"title":"operational-disconnect","disconnect_type":"UpstreamOperationalDisconnect","detail":"maintenance","type":"https://api.twitter.com/2/problems/operational-disconnect"}]}`
+
+	warning := parseWarning([]byte(message))
+
+	if warning == nil || len(warning.Errors) != 1 {
+		t.Fatalf("got %v, want one error", warning)
+	}
+
+	want := StreamErrorPayload{
+		Title:          "operational-disconnect",
+		Detail:         "maintenance",
+		Type:           "https://api.twitter.com/2/problems/operational-disconnect",
+		DisconnectType: "UpstreamOperationalDisconnect",
+	}
+	if warning.Errors[0] != want {
+		t.Errorf("got %+v, want %+v", warning.Errors[0], want)
+	}
+}
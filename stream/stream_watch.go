@@ -0,0 +1,52 @@
+package stream
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// connectionWatch closes the connection if its timer fires before it is stopped or reset,
+// so the pending read fails and the read error can be replaced with why the connection was closed.
+type connectionWatch struct {
+	timer   *time.Timer
+	timeout time.Duration
+	err     error
+	fired   int32
+}
+
+// watchConnection starts a watch that closes the connection after `timeout`, or returns nil if `timeout` is 0.
+func (s *Stream) watchConnection(timeout time.Duration, err error) *connectionWatch {
+	if timeout <= 0 {
+		return nil
+	}
+
+	w := &connectionWatch{timeout: timeout, err: err}
+	w.timer = time.AfterFunc(timeout, func() {
+		atomic.StoreInt32(&w.fired, 1)
+		s.closeBody()
+	})
+	return w
+}
+
+// reset restarts the timer. It is safe to call on a nil watch.
+func (w *connectionWatch) reset() {
+	if w != nil {
+		w.timer.Reset(w.timeout)
+	}
+}
+
+// stop stops the timer. It is safe to call on a nil watch.
+func (w *connectionWatch) stop() {
+	if w != nil {
+		w.timer.Stop()
+	}
+}
+
+// wrap returns the error of the watch if it closed the connection, or the read error otherwise.
+func (w *connectionWatch) wrap(err error) error {
+	if w != nil && atomic.LoadInt32(&w.fired) == 1 {
+		return fmt.Errorf("%w within %v", w.err, w.timeout)
+	}
+	return err
+}
@@ -0,0 +1,22 @@
+package stream
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrDataWatchdog is returned when WithDataWatchdog closed a connection on which no message arrived in time.
+var ErrDataWatchdog = errors.New("no messages arrived")
+
+// WithDataWatchdog closes the connection with ErrDataWatchdog when no message arrives within `expectedInterval`,
+// so WithAutoReconnect reconnects. Keep-alives don't count, unlike WithFirstDataTimeout and read deadlines which
+// watch raw bytes, so it catches connections that stay alive but silently stopped delivering tweets.
+//
+// This is a heuristic: it is only meant for rule sets that match often enough for silence to signal a failure,
+// and a quiet period longer than `expectedInterval` will cause a needless reconnect. Without WithAutoReconnect,
+// the stream ends with the error. Defaults to 0, which never closes the connection.
+func WithDataWatchdog(expectedInterval time.Duration) Option {
+	return func(s *Stream) {
+		s.dataWatchdog = expectedInterval
+	}
+}
@@ -0,0 +1,63 @@
+package stream
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"dev.freespoke.com/twitter-stream/httpclient"
+)
+
+// givenKeepAliveStreamClient returns a client whose connections send a tweet, then only keep-alives.
+func givenKeepAliveStreamClient(connections *int) httpclient.IHttpClient {
+	mockClient := httpclient.NewHttpClientMock("foobar")
+	mockClient.MockGetSearchStream = func(queryParams *url.Values) (*http.Response, error) {
+		*connections++
+		if *connections > 2 {
+			return nil, httpclient.ErrConnectionLimit
+		}
+
+		reader, writer := io.Pipe()
+		go func() {
+			writer.Write([]byte("{\"data\":{\"id\":\"1\"}}\r\n"))
+			for {
+				time.Sleep(5 * time.Millisecond)
+				if _, err := writer.Write([]byte("\r\n")); err != nil {
+					return
+				}
+			}
+		}()
+		return &http.Response{StatusCode: http.StatusOK, Body: reader}, nil
+	}
+	return mockClient
+}
+
+func TestDataWatchdogReconnectsWhenOnlyKeepAlivesArrive(t *testing.T) {
+	var connections int
+	instance := NewStream(givenKeepAliveStreamClient(&connections), NewStreamResponseBodyReader(),
+		WithAutoReconnect(), WithDataWatchdog(30*time.Millisecond)).(*Stream)
+	instance.backoff = BackoffFunc(func(attempt int) time.Duration { return 0 })
+
+	messages := drain(t, instance)
+
+	if connections != 3 {
+		t.Errorf("got %d connection attempts, want a reconnect after each silent connection", connections)
+	}
+	if len(messages) != 3 || !errors.Is(messages[2].Err, httpclient.ErrConnectionLimit) {
+		t.Errorf("got %+v, want a tweet per connection then the fatal error", messages)
+	}
+}
+
+func TestDataWatchdogEndsTheStreamWithoutAutoReconnect(t *testing.T) {
+	var connections int
+	instance := NewStream(givenKeepAliveStreamClient(&connections), NewStreamResponseBodyReader(), WithDataWatchdog(30*time.Millisecond))
+
+	messages := drain(t, instance)
+
+	if len(messages) != 2 || !errors.Is(messages[1].Err, ErrDataWatchdog) {
+		t.Errorf("got %+v, want the tweet then %v", messages, ErrDataWatchdog)
+	}
+}
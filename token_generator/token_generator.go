@@ -3,6 +3,7 @@ package token_generator
 import (
 	"encoding/base64"
 	"encoding/json"
+	"sync"
 
 	"dev.freespoke.com/twitter-stream/httpclient"
 )
@@ -12,11 +13,15 @@ type (
 	ITokenGenerator interface {
 		RequestBearerToken() (*RequestBearerTokenResponse, error)
 		SetApiKeyAndSecret(apiKey, apiSecret string) ITokenGenerator
+		BearerToken() (*RequestBearerTokenResponse, error)
+		InvalidateToken()
 	}
 	TokenGenerator struct {
 		httpClient httpclient.IHttpClient
 		apiKey     string
 		apiSecret  string
+		mu         sync.Mutex
+		token      *RequestBearerTokenResponse
 	}
 	RequestBearerTokenResponse struct {
 		TokenType   string `json:"token_type"`
@@ -29,14 +34,50 @@ func NewTokenGenerator(httpClient httpclient.IHttpClient) ITokenGenerator {
 }
 
 // SetApiKeyAndSecret sets the apiKey and apiSecret fields for the TokenGenerator instance.
+// The token cached by BearerToken is invalidated.
 func (a *TokenGenerator) SetApiKeyAndSecret(apiKey, apiSecret string) ITokenGenerator {
+	a.mu.Lock()
+	defer a.mu.Unlock()
 	a.apiKey = apiKey
 	a.apiSecret = apiSecret
+	a.token = nil
 	return a
 }
 
+// BearerToken returns the cached bearer token, requesting it with RequestBearerToken the first time.
+// Concurrent callers wait for a single request and share its token. Errors and empty tokens are not cached,
+// so the next call requests again. Use InvalidateToken to request a new token, e.g. after it was rejected.
+func (a *TokenGenerator) BearerToken() (*RequestBearerTokenResponse, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.token != nil {
+		return a.token, nil
+	}
+
+	token, err := a.RequestBearerToken()
+	if err != nil {
+		return nil, err
+	}
+	if token.AccessToken != "" {
+		a.token = token
+	}
+	return token, nil
+}
+
+// InvalidateToken discards the token cached by BearerToken, so the next call requests a new one.
+func (a *TokenGenerator) InvalidateToken() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.token = nil
+}
+
 // RequestBearerToken requests a bearer token from twitter using the apiKey and apiSecret.
 func (a *TokenGenerator) RequestBearerToken() (*RequestBearerTokenResponse, error) {
+	url, err := a.httpClient.GenerateUrl("token", nil)
+
+	if err != nil {
+		return nil, err
+	}
 
 	resp, err := a.httpClient.NewHttpRequest(&httpclient.RequestOpts{
 		Headers: []struct {
@@ -47,7 +88,7 @@ func (a *TokenGenerator) RequestBearerToken() (*RequestBearerTokenResponse, erro
 			{"Authorization", "Basic " + a.base64EncodeKeys()},
 		},
 		Method: "POST",
-		Url:    httpclient.Endpoints["token"],
+		Url:    url,
 		Body:   "grant_type=client_credentials",
 	})
 
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
+	"sync"
 	"testing"
 
 	"dev.freespoke.com/twitter-stream/httpclient"
@@ -20,7 +22,8 @@ func TestSetApiKeyAndSecret(t *testing.T) {
 		{"", "", TokenGenerator{apiKey: "", apiSecret: ""}},
 	}
 
-	for i, tt := range tests {
+	for i := range tests {
+		tt := &tests[i]
 		testName := fmt.Sprintf("(%d) %s %s", i, tt.apiKey, tt.apiSecret)
 		t.Run(testName, func(t *testing.T) {
 			result := &TokenGenerator{httpClient: httpclient.NewHttpClientMock("")}
@@ -68,6 +71,9 @@ func TestRequestBearerToken(t *testing.T) {
 		t.Run(testName, func(t *testing.T) {
 			mockClient := httpclient.NewHttpClientMock("")
 			mockClient.MockNewHttpRequest = tt.mockRequest
+			mockClient.MockGenerateUrl = func(name string, queryParams *url.Values) (string, error) {
+				return "https://api.twitter.com/oauth2/token", nil
+			}
 
 			instance := NewTokenGenerator(mockClient)
 			instance.SetApiKeyAndSecret("SomeKey", "SomeSecret")
@@ -92,3 +98,44 @@ func TestRequestBearerToken(t *testing.T) {
 		})
 	}
 }
+
+func TestBearerToken(t *testing.T) {
+	var mu sync.Mutex
+	var requests int
+	mockClient := httpclient.NewHttpClientMock("")
+	mockClient.MockGenerateUrl = func(name string, queryParams *url.Values) (string, error) {
+		return "https://api.twitter.com/oauth2/token", nil
+	}
+	mockClient.MockNewHttpRequest = func(opts *httpclient.RequestOpts) (*http.Response, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		requests++
+		json := fmt.Sprintf(`{"token_type": "bearer", "access_token": "token%d"}`, requests)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(bytes.NewReader([]byte(json))),
+		}, nil
+	}
+	instance := NewTokenGenerator(mockClient).SetApiKeyAndSecret("SomeKey", "SomeSecret")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if token, err := instance.BearerToken(); err != nil || token.AccessToken != "token1" {
+				t.Errorf("got %+v, %v, want token1", token, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if requests != 1 {
+		t.Errorf("got %d requests, want 1 shared by every caller", requests)
+	}
+
+	instance.InvalidateToken()
+	if token, err := instance.BearerToken(); err != nil || token.AccessToken != "token2" {
+		t.Errorf("got %+v, %v, want a new token after invalidating", token, err)
+	}
+}
@@ -0,0 +1,27 @@
+package token_generator
+
+import (
+	"errors"
+
+	"dev.freespoke.com/twitter-stream/httpclient"
+)
+
+// ErrEmptyToken is returned by Verify when the response has no access token, e.g. because Twitter
+// answered the token request with an error. Use errors.Is to detect it.
+var ErrEmptyToken = errors.New("bearer token is empty")
+
+// Verify checks that Twitter accepts the bearer token, so bad credentials are reported at startup rather than
+// when the stream connects. It makes a single read-only request, listing the stream rules of the app.
+// A rejected token returns an error wrapping `httpclient.ErrUnauthorized`. Pass the options of the clients
+// the token is used with, such as `httpclient.WithBaseURL`.
+func (r *RequestBearerTokenResponse) Verify(opts ...httpclient.Option) error {
+	if r.AccessToken == "" {
+		return ErrEmptyToken
+	}
+
+	res, err := httpclient.NewHttpClient(r.AccessToken, opts...).GetRules()
+	if err != nil {
+		return err
+	}
+	return res.Body.Close()
+}
@@ -0,0 +1,45 @@
+package token_generator
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"dev.freespoke.com/twitter-stream/httpclient"
+)
+
+func TestVerify(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/2/tweets/search/stream/rules" {
+			t.Errorf("got %s %s, want the rules listed", r.Method, r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer good" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"meta": {"sent": "today"}}`))
+	}))
+	defer server.Close()
+
+	var tests = []struct {
+		token string
+		err   error
+	}{
+		{"good", nil},
+		{"bad", httpclient.ErrUnauthorized},
+		{"", ErrEmptyToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.token, func(t *testing.T) {
+			token := &RequestBearerTokenResponse{TokenType: "bearer", AccessToken: tt.token}
+
+			err := token.Verify(httpclient.WithBaseURL(server.URL))
+
+			if !errors.Is(err, tt.err) || (tt.err == nil && err != nil) {
+				t.Errorf("got err %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
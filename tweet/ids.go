@@ -0,0 +1,172 @@
+package tweet
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// flexibleID decodes an id sent either as a JSON string or as a JSON number, keeping the exact digits.
+// Twitter sends ids as strings because they exceed the safe integer range of JavaScript, and decoding a number
+// into a float64 would silently lose precision. The typed model keeps every id field a plain string, and uses
+// flexibleID in its UnmarshalJSON methods.
+type flexibleID string
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (id *flexibleID) UnmarshalJSON(b []byte) error {
+	if bytes.Equal(b, []byte("null")) {
+		return nil
+	}
+	if len(b) > 0 && b[0] == '"' {
+		return json.Unmarshal(b, (*string)(id))
+	}
+
+	for i, c := range b {
+		if (c < '0' || c > '9') && !(i == 0 && c == '-') {
+			return fmt.Errorf("tweet: id %s is not a string or an integer", b)
+		}
+	}
+	*id = flexibleID(b)
+	return nil
+}
+
+// flexibleIDs converts decoded ids to strings.
+func flexibleIDs(ids []flexibleID) []string {
+	if ids == nil {
+		return nil
+	}
+	strs := make([]string, len(ids))
+	for i, id := range ids {
+		strs[i] = string(id)
+	}
+	return strs
+}
+
+// UnmarshalJSON implements json.Unmarshaler, accepting numeric ids.
+func (t *Tweet) UnmarshalJSON(b []byte) error {
+	type plain Tweet
+	aux := struct {
+		*plain
+		ID             flexibleID `json:"id"`
+		AuthorID       flexibleID `json:"author_id"`
+		ConversationID flexibleID `json:"conversation_id"`
+	}{plain: (*plain)(t)}
+	if err := json.Unmarshal(b, &aux); err != nil {
+		return err
+	}
+	t.ID, t.AuthorID, t.ConversationID = string(aux.ID), string(aux.AuthorID), string(aux.ConversationID)
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler, accepting numeric ids.
+func (m *MentionEntity) UnmarshalJSON(b []byte) error {
+	type plain MentionEntity
+	aux := struct {
+		*plain
+		ID flexibleID `json:"id"`
+	}{plain: (*plain)(m)}
+	if err := json.Unmarshal(b, &aux); err != nil {
+		return err
+	}
+	m.ID = string(aux.ID)
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler, accepting numeric ids.
+func (a *Attachments) UnmarshalJSON(b []byte) error {
+	type plain Attachments
+	aux := struct {
+		*plain
+		PollIDs []flexibleID `json:"poll_ids"`
+	}{plain: (*plain)(a)}
+	if err := json.Unmarshal(b, &aux); err != nil {
+		return err
+	}
+	a.PollIDs = flexibleIDs(aux.PollIDs)
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler, accepting numeric ids.
+func (r *ReferencedTweet) UnmarshalJSON(b []byte) error {
+	type plain ReferencedTweet
+	aux := struct {
+		*plain
+		ID flexibleID `json:"id"`
+	}{plain: (*plain)(r)}
+	if err := json.Unmarshal(b, &aux); err != nil {
+		return err
+	}
+	r.ID = string(aux.ID)
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler, accepting numeric ids.
+func (d *ContextAnnotationDomain) UnmarshalJSON(b []byte) error {
+	type plain ContextAnnotationDomain
+	aux := struct {
+		*plain
+		ID flexibleID `json:"id"`
+	}{plain: (*plain)(d)}
+	if err := json.Unmarshal(b, &aux); err != nil {
+		return err
+	}
+	d.ID = string(aux.ID)
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler, accepting numeric ids.
+func (e *ContextAnnotationEntity) UnmarshalJSON(b []byte) error {
+	type plain ContextAnnotationEntity
+	aux := struct {
+		*plain
+		ID flexibleID `json:"id"`
+	}{plain: (*plain)(e)}
+	if err := json.Unmarshal(b, &aux); err != nil {
+		return err
+	}
+	e.ID = string(aux.ID)
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler, accepting numeric ids.
+func (p *Poll) UnmarshalJSON(b []byte) error {
+	type plain Poll
+	aux := struct {
+		*plain
+		ID flexibleID `json:"id"`
+	}{plain: (*plain)(p)}
+	if err := json.Unmarshal(b, &aux); err != nil {
+		return err
+	}
+	p.ID = string(aux.ID)
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler, accepting numeric ids.
+func (u *User) UnmarshalJSON(b []byte) error {
+	type plain User
+	aux := struct {
+		*plain
+		ID            flexibleID `json:"id"`
+		PinnedTweetID flexibleID `json:"pinned_tweet_id"`
+	}{plain: (*plain)(u)}
+	if err := json.Unmarshal(b, &aux); err != nil {
+		return err
+	}
+	u.ID, u.PinnedTweetID = string(aux.ID), string(aux.PinnedTweetID)
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler, accepting numeric ids.
+func (r *MatchingRule) UnmarshalJSON(b []byte) error {
+	type plain MatchingRule
+	aux := struct {
+		*plain
+		ID flexibleID `json:"id"`
+	}{plain: (*plain)(r)}
+	if err := json.Unmarshal(b, &aux); err != nil {
+		return err
+	}
+	r.ID = string(aux.ID)
+	return nil
+}
@@ -0,0 +1,51 @@
+package tweet
+
+import "testing"
+
+func TestUnmarshalAcceptsNumericIDs(t *testing.T) {
+	payload := `{
+		"data": {
+			"id": 1445880548472328192,
+			"author_id": "2244994945",
+			"conversation_id": 1445880548472328192,
+			"created_at": "2021-10-06T22:40:24.000Z",
+			"referenced_tweets": [{"type": "quoted", "id": 1445078208190291968}],
+			"attachments": {"poll_ids": [1199786642468413448]}
+		},
+		"includes": {"users": [{"id": 2244994945, "username": "TwitterDev", "pinned_tweet_id": 1430984356139470849}]},
+		"matching_rules": [{"id": 1445880431593062400, "tag": "dev"}]
+	}`
+
+	result, err := Unmarshal([]byte(payload))
+	if err != nil {
+		t.Fatalf("got err %v", err)
+	}
+
+	if result.Data.ID != "1445880548472328192" || result.Data.ConversationID != "1445880548472328192" {
+		t.Errorf("got %q and %q, want the exact digits", result.Data.ID, result.Data.ConversationID)
+	}
+	if result.Data.AuthorID != "2244994945" || result.Data.CreatedAt.IsZero() {
+		t.Errorf("got %+v, want string ids and other fields decoded as before", result.Data)
+	}
+	if result.Data.ReferencedTweets[0].ID != "1445078208190291968" {
+		t.Errorf("got %q, want the referenced tweet id", result.Data.ReferencedTweets[0].ID)
+	}
+	if result.Data.Attachments.PollIDs[0] != "1199786642468413448" {
+		t.Errorf("got %q, want the poll id", result.Data.Attachments.PollIDs[0])
+	}
+	if result.Includes.Users[0].ID != "2244994945" || result.Includes.Users[0].Username != "TwitterDev" ||
+		result.Includes.Users[0].PinnedTweetID != "1430984356139470849" {
+		t.Errorf("got %+v, want the user id", result.Includes.Users[0])
+	}
+	if result.MatchingRules[0].ID != "1445880431593062400" || result.MatchingRules[0].Tag != "dev" {
+		t.Errorf("got %+v, want the rule id", result.MatchingRules[0])
+	}
+}
+
+func TestUnmarshalRejectsNonIntegerIDs(t *testing.T) {
+	for _, payload := range []string{`{"data": {"id": 1.5}}`, `{"data": {"id": true}}`} {
+		if _, err := Unmarshal([]byte(payload)); err == nil {
+			t.Errorf("expected err for %s", payload)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package tweet
+
+import "strings"
+
+// Types of a ReferencedTweet.
+const (
+	ReferenceRepliedTo = "replied_to"
+	ReferenceQuoted    = "quoted"
+	ReferenceRetweeted = "retweeted"
+)
+
+// ResolvedTweet is a referenced tweet joined with its object from Includes.
+// When the referenced tweet isn't present in Includes, Included is false and Tweet only has its ID set.
+type ResolvedTweet struct {
+	Type     string
+	Tweet    Tweet
+	Included bool
+}
+
+// ReferencedTweets resolves the tweets that the streamed tweet replies to, quotes, or retweets.
+// Referenced tweets are only present in Includes when `AddExpansion("referenced_tweets.id")` is requested.
+func (r *StreamResponse) ReferencedTweets() []ResolvedTweet {
+	return r.ReferencedTweetsOf(r.Data)
+}
+
+// ReferencedTweetsOf resolves the tweets referenced by `t` from the Includes of this message.
+// Call it with the Tweet of a ResolvedTweet to walk a conversation further, as far as Includes allows.
+func (r *StreamResponse) ReferencedTweetsOf(t Tweet) []ResolvedTweet {
+	if len(t.ReferencedTweets) == 0 {
+		return nil
+	}
+
+	resolved := make([]ResolvedTweet, 0, len(t.ReferencedTweets))
+	for _, ref := range t.ReferencedTweets {
+		result := ResolvedTweet{Type: ref.Type, Tweet: Tweet{ID: ref.ID}}
+		if included, ok := r.includedTweet(ref.ID); ok {
+			result.Tweet = included
+			result.Included = true
+		}
+		resolved = append(resolved, result)
+	}
+
+	return resolved
+}
+
+func (r *StreamResponse) includedTweet(id string) (Tweet, bool) {
+	for _, t := range r.Includes.Tweets {
+		if t.ID == id {
+			return t, true
+		}
+	}
+	return Tweet{}, false
+}
+
+// Media returns the media attached to the streamed tweet, joined from Includes by the attachment media keys,
+// in the order of the keys. Media missing from Includes is skipped, and nil is returned when there are no attachments.
+func (r *StreamResponse) Media() []Media {
+	if r.Data.Attachments == nil || len(r.Data.Attachments.MediaKeys) == 0 {
+		return nil
+	}
+
+	media := make([]Media, 0, len(r.Data.Attachments.MediaKeys))
+	for _, key := range r.Data.Attachments.MediaKeys {
+		for _, m := range r.Includes.Media {
+			if m.MediaKey == key {
+				media = append(media, m)
+				break
+			}
+		}
+	}
+	return media
+}
+
+// Poll returns the poll attached to the streamed tweet, joined from Includes by the attachment poll id.
+// It returns false when the tweet has no poll or the poll isn't present in Includes.
+func (r *StreamResponse) Poll() (Poll, bool) {
+	if r.Data.Attachments == nil {
+		return Poll{}, false
+	}
+
+	for _, id := range r.Data.Attachments.PollIDs {
+		for _, p := range r.Includes.Polls {
+			if p.ID == id {
+				return p, true
+			}
+		}
+	}
+	return Poll{}, false
+}
+
+// MatchingTags returns the tags of the rules that matched the streamed tweet, in the order Twitter sent them.
+func (r *StreamResponse) MatchingTags() []string {
+	tags := make([]string, 0, len(r.MatchingRules))
+	for _, rule := range r.MatchingRules {
+		tags = append(tags, rule.Tag)
+	}
+	return tags
+}
+
+// GroupByConversation groups tweets by their ConversationID, which is requested with
+// `AddTweetField("conversation_id")`. Tweets keep their order within a group.
+// Tweets without a ConversationID are grouped under the empty string key "".
+func GroupByConversation(tweets []Tweet) map[string][]Tweet {
+	grouped := make(map[string][]Tweet)
+	for _, t := range tweets {
+		grouped[t.ConversationID] = append(grouped[t.ConversationID], t)
+	}
+	return grouped
+}
+
+// IsLang reports whether the tweet is in the language `code`, ignoring case. A code without a region like "zh"
+// also matches tweets with a region like "zh-tw". Lang is only set when `AddTweetField("lang")` is requested,
+// and is "und" when Twitter couldn't detect the language.
+func (t Tweet) IsLang(code string) bool {
+	if code == "" {
+		return false
+	}
+	if strings.EqualFold(t.Lang, code) {
+		return true
+	}
+	return len(t.Lang) > len(code) && t.Lang[len(code)] == '-' && strings.EqualFold(t.Lang[:len(code)], code)
+}
+
+// TotalEngagements returns the sum of retweets, replies, likes and quotes. Impressions are views, not
+// engagements, so they are not counted. It returns 0 if public_metrics were not requested.
+func (m *PublicMetrics) TotalEngagements() int {
+	if m == nil {
+		return 0
+	}
+	return m.RetweetCount + m.ReplyCount + m.LikeCount + m.QuoteCount
+}
+
+// EngagementRate returns TotalEngagements per impression, or 0 if there were no impressions
+// or public_metrics were not requested.
+func (m *PublicMetrics) EngagementRate() float64 {
+	if m == nil || m.ImpressionCount == 0 {
+		return 0
+	}
+	return float64(m.TotalEngagements()) / float64(m.ImpressionCount)
+}
+
+// LatLon returns the latitude and longitude of a geotagged tweet with a precise location. ok is false when the
+// tweet has no point coordinates, including when geo was not requested.
+func (g *Geo) LatLon() (lat, lon float64, ok bool) {
+	if g == nil || g.Coordinates == nil || g.Coordinates.Type != "Point" || len(g.Coordinates.Coordinates) < 2 {
+		return 0, 0, false
+	}
+	return g.Coordinates.Coordinates[1], g.Coordinates.Coordinates[0], true
+}
@@ -0,0 +1,169 @@
+package tweet
+
+import "testing"
+
+func TestReferencedTweetsJoinsIncludes(t *testing.T) {
+	payload := `{
+		"data": {
+			"id": "3",
+			"text": "quoting a reply",
+			"referenced_tweets": [{"type": "quoted", "id": "2"}, {"type": "replied_to", "id": "9"}]
+		},
+		"includes": {
+			"tweets": [
+				{"id": "2", "text": "a reply", "referenced_tweets": [{"type": "replied_to", "id": "1"}]},
+				{"id": "1", "text": "the original"}
+			]
+		}
+	}`
+
+	result, err := Unmarshal([]byte(payload))
+	if err != nil {
+		t.Fatalf("got err %v", err)
+	}
+
+	refs := result.ReferencedTweets()
+
+	if len(refs) != 2 {
+		t.Fatalf("got %d references, want 2", len(refs))
+	}
+
+	if refs[0].Type != ReferenceQuoted || !refs[0].Included || refs[0].Tweet.Text != "a reply" {
+		t.Errorf("got %+v, want included quoted tweet", refs[0])
+	}
+
+	if refs[1].Type != ReferenceRepliedTo || refs[1].Included || refs[1].Tweet.ID != "9" {
+		t.Errorf("got %+v, want partial replied_to tweet with id 9", refs[1])
+	}
+
+	nested := result.ReferencedTweetsOf(refs[0].Tweet)
+	if len(nested) != 1 || nested[0].Tweet.Text != "the original" {
+		t.Errorf("got %+v, want the original tweet", nested)
+	}
+}
+
+func TestReferencedTweetsIsNilWithoutReferences(t *testing.T) {
+	result, err := Unmarshal([]byte(`{"data": {"id": "1", "text": "hello"}}`))
+	if err != nil {
+		t.Fatalf("got err %v", err)
+	}
+
+	if result.ReferencedTweets() != nil {
+		t.Errorf("got %v, want nil", result.ReferencedTweets())
+	}
+}
+
+func TestMatchingTags(t *testing.T) {
+	result, err := Unmarshal([]byte(`{"data": {"id": "1"}, "matching_rules": [{"id": "1", "tag": "cats"}, {"id": "2", "tag": "pets"}]}`))
+	if err != nil {
+		t.Fatalf("got err %v", err)
+	}
+
+	tags := result.MatchingTags()
+	if len(tags) != 2 || tags[0] != "cats" || tags[1] != "pets" {
+		t.Errorf("got %v, want [cats pets]", tags)
+	}
+}
+
+func TestMediaAndPollJoinAttachments(t *testing.T) {
+	payload := `{
+		"data": {
+			"id": "1",
+			"text": "look and vote",
+			"attachments": {"media_keys": ["3_2", "3_1"], "poll_ids": ["7"]}
+		},
+		"includes": {
+			"media": [
+				{"media_key": "3_1", "type": "photo", "url": "https://pbs.twimg.com/1.jpg"},
+				{"media_key": "3_2", "type": "video"},
+				{"media_key": "3_9", "type": "photo"}
+			],
+			"polls": [{"id": "7", "options": [{"position": 1, "label": "yes", "votes": 3}]}]
+		}
+	}`
+
+	result, err := Unmarshal([]byte(payload))
+	if err != nil {
+		t.Fatalf("got err %v", err)
+	}
+
+	media := result.Media()
+	if len(media) != 2 || media[0].MediaKey != "3_2" || media[1].URL != "https://pbs.twimg.com/1.jpg" {
+		t.Errorf("got %+v, want media 3_2 and 3_1", media)
+	}
+
+	poll, ok := result.Poll()
+	if !ok || len(poll.Options) != 1 || poll.Options[0].Votes != 3 {
+		t.Errorf("got %+v, want poll 7", poll)
+	}
+}
+
+func TestMediaAndPollWithoutAttachments(t *testing.T) {
+	result, err := Unmarshal([]byte(`{"data": {"id": "1", "text": "hello"}, "includes": {"media": [{"media_key": "3_1"}]}}`))
+	if err != nil {
+		t.Fatalf("got err %v", err)
+	}
+
+	if result.Media() != nil {
+		t.Errorf("got %v, want nil", result.Media())
+	}
+
+	if _, ok := result.Poll(); ok {
+		t.Errorf("expected no poll")
+	}
+}
+
+func TestGroupByConversation(t *testing.T) {
+	var tweets []Tweet
+	for _, payload := range []string{
+		`{"data": {"id": "1", "conversation_id": "1"}}`,
+		`{"data": {"id": "2", "conversation_id": "1"}}`,
+		`{"data": {"id": "3", "conversation_id": "1445080037264928769"}}`,
+		`{"data": {"id": "4"}}`,
+	} {
+		result, err := Unmarshal([]byte(payload))
+		if err != nil {
+			t.Fatalf("got err %v", err)
+		}
+		tweets = append(tweets, result.Data)
+	}
+
+	grouped := GroupByConversation(tweets)
+
+	if len(grouped["1"]) != 2 || grouped["1"][1].ID != "2" {
+		t.Errorf("got %v, want tweets 1 and 2", grouped["1"])
+	}
+
+	// conversation ids are too large for a float64, so they must stay strings
+	if len(grouped["1445080037264928769"]) != 1 {
+		t.Errorf("got %v, want tweet 3", grouped)
+	}
+
+	if len(grouped[""]) != 1 || grouped[""][0].ID != "4" {
+		t.Errorf("got %v, want tweet 4 without a conversation", grouped[""])
+	}
+}
+
+func TestIsLang(t *testing.T) {
+	var tests = []struct {
+		lang string
+		code string
+		want bool
+	}{
+		{"en", "en", true},
+		{"en", "EN", true},
+		{"en", "es", false},
+		{"zh-tw", "zh", true},
+		{"zh-tw", "zh-TW", true},
+		{"zh", "zh-tw", false},
+		{"ena", "en", false},
+		{"", "en", false},
+		{"en", "", false},
+	}
+
+	for _, tt := range tests {
+		if got := (Tweet{Lang: tt.lang}).IsLang(tt.code); got != tt.want {
+			t.Errorf("got %v for %q IsLang(%q), want %v", got, tt.lang, tt.code, tt.want)
+		}
+	}
+}
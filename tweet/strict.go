@@ -0,0 +1,134 @@
+package tweet
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ErrUnknownField is returned by Unmarshal with DisallowUnknownFields, and by CheckUnknownFields, when a message
+// has a field that the model doesn't decode. Use errors.Is to detect it.
+var ErrUnknownField = errors.New("tweet: unknown field")
+
+type (
+	// UnmarshalOption configures Unmarshal.
+	UnmarshalOption func(*unmarshalOptions)
+
+	unmarshalOptions struct {
+		disallowUnknownFields bool
+	}
+)
+
+// DisallowUnknownFields makes Unmarshal fail with ErrUnknownField when the message has a field that StreamResponse
+// doesn't decode, the way `json.Decoder.DisallowUnknownFields` does, but also inside the types of the model that
+// decode themselves. Use it in tests to notice when Twitter sends fields the model doesn't capture yet, and keep
+// production lenient.
+func DisallowUnknownFields() UnmarshalOption {
+	return func(o *unmarshalOptions) {
+		o.disallowUnknownFields = true
+	}
+}
+
+// NewUnmarshalHook returns an UnmarshalHook decoding with `opts`, for `stream.SetUnmarshalHook`.
+func NewUnmarshalHook(opts ...UnmarshalOption) func(bytes []byte) (interface{}, error) {
+	return func(bytes []byte) (interface{}, error) {
+		return Unmarshal(bytes, opts...)
+	}
+}
+
+// CheckUnknownFields returns ErrUnknownField, with the path of the field, when the JSON in `b` has an object field
+// that the type of `v` has no field for. Field names are matched like encoding/json does, ignoring case.
+// Values decoded into an interface{}, a map or a json.RawMessage accept any field.
+func CheckUnknownFields(b []byte, v interface{}) error {
+	var raw interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	return checkFields(raw, reflect.TypeOf(v), "")
+}
+
+// checkFields walks the decoded JSON `raw` along the type `t`.
+func checkFields(raw interface{}, t reflect.Type, path string) error {
+	if t == nil {
+		return nil
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch value := raw.(type) {
+	case map[string]interface{}:
+		switch t.Kind() {
+		case reflect.Map:
+			for key, field := range value {
+				if err := checkFields(field, t.Elem(), join(path, key)); err != nil {
+					return err
+				}
+			}
+		case reflect.Struct:
+			fields := jsonFields(t)
+			for key, field := range value {
+				ft, ok := fields[strings.ToLower(key)]
+				if !ok {
+					return fmt.Errorf("%w: %q", ErrUnknownField, join(path, key))
+				}
+				if err := checkFields(field, ft, join(path, key)); err != nil {
+					return err
+				}
+			}
+		}
+	case []interface{}:
+		if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+			for i, item := range value {
+				if err := checkFields(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// jsonFields returns the types of the fields of struct `t` by lower-cased JSON name, including promoted fields.
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+
+		ft := field.Type
+		for ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if field.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			for promoted, pt := range jsonFields(ft) {
+				if _, ok := fields[promoted]; !ok {
+					fields[promoted] = pt
+				}
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[strings.ToLower(name)] = field.Type
+	}
+	return fields
+}
+
+// join appends a field to a path of fields.
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
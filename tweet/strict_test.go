@@ -0,0 +1,39 @@
+package tweet
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDisallowUnknownFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+		field   string
+	}{
+		{name: "known fields", payload: `{"data": {"id": 1, "Text": "hi", "geo": {"place_id": "p"}}, "matching_rules": [{"id": "1", "tag": "a"}]}`},
+		{name: "unknown top-level field", payload: `{"data": {"id": "1"}, "extra": true}`, field: `"extra"`},
+		{name: "unknown field of a tweet", payload: `{"data": {"id": "1", "edit_controls": {}}}`, field: `"data.edit_controls"`},
+		{name: "unknown field in a slice", payload: `{"data": {"id": "1"}, "matching_rules": [{"id": "1", "tag": "a", "value": "cats"}]}`, field: `"matching_rules[0].value"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Unmarshal([]byte(tt.payload)); err != nil {
+				t.Fatalf("got err %v when lenient", err)
+			}
+
+			_, err := Unmarshal([]byte(tt.payload), DisallowUnknownFields())
+			if tt.field == "" {
+				if err != nil {
+					t.Errorf("got err %v, want none", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrUnknownField) || !strings.Contains(err.Error(), tt.field) {
+				t.Errorf("got err %v, want %v for %s", err, ErrUnknownField, tt.field)
+			}
+		})
+	}
+}
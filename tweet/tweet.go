@@ -0,0 +1,269 @@
+// Package tweet provides a typed model of the messages delivered by Twitter's v2 filtered stream.
+package tweet
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ReplySettings values, returned when `AddTweetField("reply_settings")` is requested.
+// They tell who can reply to the tweet.
+const (
+	ReplySettingsEveryone       = "everyone"
+	ReplySettingsMentionedUsers = "mentionedUsers"
+	ReplySettingsFollowing      = "following"
+)
+
+type (
+	// StreamResponse is a single message delivered by the filtered stream.
+	// Read more at https://developer.twitter.com/en/docs/twitter-api/tweets/filtered-stream/api-reference/get-tweets-search-stream.
+	StreamResponse struct {
+		Data          Tweet          `json:"data"`
+		Includes      Includes       `json:"includes"`
+		MatchingRules []MatchingRule `json:"matching_rules"`
+	}
+
+	// Tweet is the "data" object of a stream message.
+	// Fields that were not requested with `AddTweetField` are left as their zero value.
+	Tweet struct {
+		ID                 string              `json:"id"`
+		Text               string              `json:"text"`
+		AuthorID           string              `json:"author_id,omitempty"`
+		ConversationID     string              `json:"conversation_id,omitempty"`
+		ReplySettings      string              `json:"reply_settings,omitempty"`
+		Lang               string              `json:"lang,omitempty"`
+		Source             string              `json:"source,omitempty"`
+		CreatedAt          time.Time           `json:"created_at"`
+		ContextAnnotations []ContextAnnotation `json:"context_annotations,omitempty"`
+		Withheld           *Withheld           `json:"withheld,omitempty"`
+		ReferencedTweets   []ReferencedTweet   `json:"referenced_tweets,omitempty"`
+		PublicMetrics      *PublicMetrics      `json:"public_metrics,omitempty"`
+		NonPublicMetrics   *NonPublicMetrics   `json:"non_public_metrics,omitempty"`
+		OrganicMetrics     *OrganicMetrics     `json:"organic_metrics,omitempty"`
+		PromotedMetrics    *PromotedMetrics    `json:"promoted_metrics,omitempty"`
+		Attachments        *Attachments        `json:"attachments,omitempty"`
+		Entities           *Entities           `json:"entities,omitempty"`
+		Geo                *Geo                `json:"geo,omitempty"`
+	}
+
+	// Geo is returned when `AddTweetField("geo")` is requested and the tweet is geotagged.
+	// Coordinates are only set when the user shared their precise location, which is rare;
+	// otherwise only PlaceID is, which `AddExpansion("geo.place_id")` expands.
+	Geo struct {
+		Coordinates *Coordinates `json:"coordinates,omitempty"`
+		PlaceID     string       `json:"place_id,omitempty"`
+	}
+
+	// Coordinates is a GeoJSON geometry. For a "Point", Coordinates is the longitude then the latitude.
+	Coordinates struct {
+		Type        string    `json:"type"`
+		Coordinates []float64 `json:"coordinates"`
+	}
+
+	// Entities is returned when `AddTweetField("entities")` is requested and the tweet text has entities.
+	// Start and End are the positions of the entity in the tweet text.
+	Entities struct {
+		Hashtags    []HashtagEntity    `json:"hashtags,omitempty"`
+		Cashtags    []HashtagEntity    `json:"cashtags,omitempty"`
+		Mentions    []MentionEntity    `json:"mentions,omitempty"`
+		URLs        []URLEntity        `json:"urls,omitempty"`
+		Annotations []AnnotationEntity `json:"annotations,omitempty"`
+	}
+
+	// HashtagEntity is a hashtag or cashtag in the tweet text, without the leading # or $.
+	HashtagEntity struct {
+		Start int    `json:"start"`
+		End   int    `json:"end"`
+		Tag   string `json:"tag"`
+	}
+
+	// MentionEntity is a mentioned user in the tweet text.
+	MentionEntity struct {
+		Start    int    `json:"start"`
+		End      int    `json:"end"`
+		Username string `json:"username"`
+		ID       string `json:"id,omitempty"`
+	}
+
+	// URLEntity is a link in the tweet text. URL is the t.co link, ExpandedURL is where it points to,
+	// and DisplayURL is the shortened form shown in the tweet.
+	URLEntity struct {
+		Start       int    `json:"start"`
+		End         int    `json:"end"`
+		URL         string `json:"url"`
+		ExpandedURL string `json:"expanded_url"`
+		DisplayURL  string `json:"display_url"`
+		UnwoundURL  string `json:"unwound_url,omitempty"`
+		MediaKey    string `json:"media_key,omitempty"`
+	}
+
+	// AnnotationEntity is a named entity Twitter detected in the tweet text, e.g. a Person or Place.
+	AnnotationEntity struct {
+		Start          int     `json:"start"`
+		End            int     `json:"end"`
+		Probability    float64 `json:"probability"`
+		Type           string  `json:"type"`
+		NormalizedText string  `json:"normalized_text"`
+	}
+
+	// Attachments links a tweet to its media and poll in Includes.
+	// It is returned when `AddTweetField("attachments")` is requested and the tweet has media or a poll.
+	Attachments struct {
+		MediaKeys []string `json:"media_keys,omitempty"`
+		PollIDs   []string `json:"poll_ids,omitempty"`
+	}
+
+	// PublicMetrics is returned when `AddTweetField("public_metrics")` is requested.
+	// It counts public engagement with the tweet when it was delivered; tweets are usually streamed right after
+	// they are created, so the counts are often zero.
+	PublicMetrics struct {
+		RetweetCount    int `json:"retweet_count"`
+		ReplyCount      int `json:"reply_count"`
+		LikeCount       int `json:"like_count"`
+		QuoteCount      int `json:"quote_count"`
+		ImpressionCount int `json:"impression_count"`
+	}
+
+	// NonPublicMetrics is returned when `AddTweetField("non_public_metrics")` is requested with user-context auth,
+	// for tweets of the authenticated user. It counts the engagement only the author can see.
+	NonPublicMetrics struct {
+		ImpressionCount   int `json:"impression_count"`
+		URLLinkClicks     int `json:"url_link_clicks"`
+		UserProfileClicks int `json:"user_profile_clicks"`
+	}
+
+	// OrganicMetrics is returned when `AddTweetField("organic_metrics")` is requested with user-context auth.
+	// It counts engagement from organic, non-promoted, contexts.
+	OrganicMetrics struct {
+		ImpressionCount   int `json:"impression_count"`
+		LikeCount         int `json:"like_count"`
+		ReplyCount        int `json:"reply_count"`
+		RetweetCount      int `json:"retweet_count"`
+		URLLinkClicks     int `json:"url_link_clicks"`
+		UserProfileClicks int `json:"user_profile_clicks"`
+	}
+
+	// PromotedMetrics is returned when `AddTweetField("promoted_metrics")` is requested with user-context auth
+	// for a tweet that was promoted. It counts engagement from promoted contexts.
+	PromotedMetrics struct {
+		ImpressionCount   int `json:"impression_count"`
+		LikeCount         int `json:"like_count"`
+		ReplyCount        int `json:"reply_count"`
+		RetweetCount      int `json:"retweet_count"`
+		URLLinkClicks     int `json:"url_link_clicks"`
+		UserProfileClicks int `json:"user_profile_clicks"`
+	}
+
+	// ReferencedTweet is a reference from a tweet to the tweet it replies to, quotes, or retweets.
+	// Use `StreamResponse.ReferencedTweets` to resolve the referenced tweet from Includes.
+	ReferencedTweet struct {
+		Type string `json:"type"`
+		ID   string `json:"id"`
+	}
+
+	// ContextAnnotation is returned when `AddTweetField("context_annotations")` is requested.
+	// Read more at https://developer.twitter.com/en/docs/twitter-api/annotations/overview.
+	ContextAnnotation struct {
+		Domain ContextAnnotationDomain `json:"domain"`
+		Entity ContextAnnotationEntity `json:"entity"`
+	}
+
+	// ContextAnnotationDomain is the domain of a ContextAnnotation, e.g. "Brand" or "Person".
+	ContextAnnotationDomain struct {
+		ID          string `json:"id"`
+		Name        string `json:"name"`
+		Description string `json:"description,omitempty"`
+	}
+
+	// ContextAnnotationEntity is the entity of a ContextAnnotation, e.g. "Twitter" or "Barack Obama".
+	ContextAnnotationEntity struct {
+		ID          string `json:"id"`
+		Name        string `json:"name"`
+		Description string `json:"description,omitempty"`
+	}
+
+	// Withheld is returned when `AddTweetField("withheld")` is requested and the tweet is withheld.
+	Withheld struct {
+		Copyright    bool     `json:"copyright"`
+		CountryCodes []string `json:"country_codes"`
+		Scope        string   `json:"scope"`
+	}
+
+	// Includes contains the objects expanded with `AddExpansion`.
+	Includes struct {
+		Users  []User  `json:"users,omitempty"`
+		Tweets []Tweet `json:"tweets,omitempty"`
+		Media  []Media `json:"media,omitempty"`
+		Polls  []Poll  `json:"polls,omitempty"`
+	}
+
+	// Media is an expanded media object found in Includes, requested with `AddExpansion("attachments.media_keys")`.
+	// Fields other than MediaKey and Type need `AddMediaField`.
+	Media struct {
+		MediaKey        string `json:"media_key"`
+		Type            string `json:"type"`
+		URL             string `json:"url,omitempty"`
+		PreviewImageURL string `json:"preview_image_url,omitempty"`
+		Width           int    `json:"width,omitempty"`
+		Height          int    `json:"height,omitempty"`
+		AltText         string `json:"alt_text,omitempty"`
+	}
+
+	// Poll is an expanded poll object found in Includes, requested with `AddExpansion("attachments.poll_ids")`.
+	// Fields other than ID and Options need `AddPollField`.
+	Poll struct {
+		ID              string       `json:"id"`
+		Options         []PollOption `json:"options"`
+		DurationMinutes int          `json:"duration_minutes,omitempty"`
+		EndDatetime     time.Time    `json:"end_datetime"`
+		VotingStatus    string       `json:"voting_status,omitempty"`
+	}
+
+	// PollOption is a choice of a Poll.
+	PollOption struct {
+		Position int    `json:"position"`
+		Label    string `json:"label"`
+		Votes    int    `json:"votes"`
+	}
+
+	// User is an expanded user object found in Includes.
+	// PinnedTweetID is only set when `AddUserField("pinned_tweet_id")` is requested and the user pinned a tweet.
+	User struct {
+		ID            string `json:"id"`
+		Name          string `json:"name"`
+		Username      string `json:"username"`
+		PinnedTweetID string `json:"pinned_tweet_id,omitempty"`
+	}
+
+	// MatchingRule is a rule that matched the tweet that was delivered.
+	MatchingRule struct {
+		ID  string `json:"id"`
+		Tag string `json:"tag"`
+	}
+)
+
+// UnmarshalHook decodes a stream message into a *StreamResponse.
+// It can be used with `stream.SetUnmarshalHook`.
+func UnmarshalHook(bytes []byte) (interface{}, error) {
+	return Unmarshal(bytes)
+}
+
+// Unmarshal decodes a stream message into a *StreamResponse.
+// Fields the model doesn't capture are ignored, unless DisallowUnknownFields is passed.
+func Unmarshal(bytes []byte, opts ...UnmarshalOption) (*StreamResponse, error) {
+	var o unmarshalOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	data := new(StreamResponse)
+	if err := json.Unmarshal(bytes, data); err != nil {
+		return nil, err
+	}
+	if o.disallowUnknownFields {
+		if err := CheckUnknownFields(bytes, data); err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
@@ -0,0 +1,244 @@
+package tweet
+
+import (
+	"testing"
+)
+
+func TestUnmarshalDecodesContextAnnotationsAndWithheld(t *testing.T) {
+	payload := `{
+		"data": {
+			"id": "1",
+			"text": "hello",
+			"context_annotations": [{
+				"domain": {"id": "45", "name": "Brand Vertical"},
+				"entity": {"id": "781974596165640193", "name": "Technology"}
+			}],
+			"withheld": {"copyright": true, "country_codes": ["DE", "FR"], "scope": "tweet"}
+		},
+		"matching_rules": [{"id": "123", "tag": "tech"}]
+	}`
+
+	result, err := Unmarshal([]byte(payload))
+
+	if err != nil {
+		t.Fatalf("got err %v", err)
+	}
+
+	if len(result.Data.ContextAnnotations) != 1 {
+		t.Fatalf("got %d context annotations, want 1", len(result.Data.ContextAnnotations))
+	}
+
+	annotation := result.Data.ContextAnnotations[0]
+	if annotation.Domain.Name != "Brand Vertical" || annotation.Entity.Name != "Technology" {
+		t.Errorf("got %+v, want Brand Vertical/Technology", annotation)
+	}
+
+	if result.Data.Withheld == nil {
+		t.Fatal("got nil withheld")
+	}
+
+	if !result.Data.Withheld.Copyright || result.Data.Withheld.Scope != "tweet" || len(result.Data.Withheld.CountryCodes) != 2 {
+		t.Errorf("got %+v, want copyright withheld tweet in 2 countries", result.Data.Withheld)
+	}
+
+	if result.MatchingRules[0].Tag != "tech" {
+		t.Errorf("got %s, want %s", result.MatchingRules[0].Tag, "tech")
+	}
+}
+
+func TestUnmarshalToleratesAbsentFields(t *testing.T) {
+	result, err := Unmarshal([]byte(`{"data": {"id": "1", "text": "hello"}}`))
+
+	if err != nil {
+		t.Fatalf("got err %v", err)
+	}
+
+	if result.Data.ContextAnnotations != nil {
+		t.Errorf("got %v, want nil context annotations", result.Data.ContextAnnotations)
+	}
+
+	if result.Data.Withheld != nil {
+		t.Errorf("got %v, want nil withheld", result.Data.Withheld)
+	}
+
+	if result.Data.Entities != nil {
+		t.Errorf("got %v, want nil entities", result.Data.Entities)
+	}
+}
+
+func TestUnmarshalHookReturnsStreamResponse(t *testing.T) {
+	result, err := UnmarshalHook([]byte(`{"data": {"id": "1", "text": "hello"}}`))
+
+	if err != nil {
+		t.Fatalf("got err %v", err)
+	}
+
+	if _, ok := result.(*StreamResponse); !ok {
+		t.Errorf("got %T, want *StreamResponse", result)
+	}
+}
+
+func TestUnmarshalDecodesOrganicAndPromotedMetrics(t *testing.T) {
+	payload := `{
+		"data": {
+			"id": "1",
+			"text": "hello",
+			"non_public_metrics": {"impression_count": 120, "url_link_clicks": 4, "user_profile_clicks": 1},
+			"organic_metrics": {"impression_count": 100, "like_count": 5, "url_link_clicks": 2},
+			"promoted_metrics": {"impression_count": 900, "user_profile_clicks": 3}
+		}
+	}`
+
+	result, err := Unmarshal([]byte(payload))
+	if err != nil {
+		t.Fatalf("got err %v", err)
+	}
+
+	if result.Data.OrganicMetrics == nil || result.Data.OrganicMetrics.ImpressionCount != 100 || result.Data.OrganicMetrics.URLLinkClicks != 2 {
+		t.Errorf("got %+v, want organic metrics", result.Data.OrganicMetrics)
+	}
+
+	if result.Data.PromotedMetrics == nil || result.Data.PromotedMetrics.UserProfileClicks != 3 {
+		t.Errorf("got %+v, want promoted metrics", result.Data.PromotedMetrics)
+	}
+
+	if want := (NonPublicMetrics{ImpressionCount: 120, URLLinkClicks: 4, UserProfileClicks: 1}); result.Data.NonPublicMetrics == nil || *result.Data.NonPublicMetrics != want {
+		t.Errorf("got %+v, want non-public metrics", result.Data.NonPublicMetrics)
+	}
+
+	absent, _ := Unmarshal([]byte(`{"data": {"id": "1"}}`))
+	if absent.Data.OrganicMetrics != nil || absent.Data.PromotedMetrics != nil || absent.Data.NonPublicMetrics != nil {
+		t.Errorf("got %+v, want nil metrics", absent.Data)
+	}
+}
+
+func TestUnmarshalDecodesPublicMetrics(t *testing.T) {
+	payload := `{
+		"data": {
+			"id": "1",
+			"text": "hello",
+			"public_metrics": {"retweet_count": 1, "reply_count": 2, "like_count": 3, "quote_count": 4, "impression_count": 20}
+		}
+	}`
+
+	result, err := Unmarshal([]byte(payload))
+	if err != nil {
+		t.Fatalf("got err %v", err)
+	}
+
+	metrics := result.Data.PublicMetrics
+	if metrics == nil || metrics.QuoteCount != 4 || metrics.ImpressionCount != 20 {
+		t.Fatalf("got %+v, want public metrics", metrics)
+	}
+	if got := metrics.TotalEngagements(); got != 10 {
+		t.Errorf("got %d engagements, want 10", got)
+	}
+	if got := metrics.EngagementRate(); got != 0.5 {
+		t.Errorf("got engagement rate %v, want 0.5", got)
+	}
+
+	absent, _ := Unmarshal([]byte(`{"data": {"id": "1"}}`))
+	if absent.Data.PublicMetrics != nil || absent.Data.PublicMetrics.TotalEngagements() != 0 || absent.Data.PublicMetrics.EngagementRate() != 0 {
+		t.Errorf("got %+v, want nil metrics counting nothing", absent.Data.PublicMetrics)
+	}
+}
+
+func TestUnmarshalDecodesGeo(t *testing.T) {
+	payload := `{
+		"data": {
+			"id": "1",
+			"text": "hello",
+			"geo": {"coordinates": {"type": "Point", "coordinates": [-73.99, 40.73]}, "place_id": "01a9a39529b27f36"}
+		}
+	}`
+
+	result, err := Unmarshal([]byte(payload))
+	if err != nil {
+		t.Fatalf("got err %v", err)
+	}
+
+	if result.Data.Geo == nil || result.Data.Geo.PlaceID != "01a9a39529b27f36" {
+		t.Fatalf("got %+v, want geo", result.Data.Geo)
+	}
+	if lat, lon, ok := result.Data.Geo.LatLon(); !ok || lat != 40.73 || lon != -73.99 {
+		t.Errorf("got %v, %v, %v, want 40.73, -73.99", lat, lon, ok)
+	}
+
+	placeOnly, _ := Unmarshal([]byte(`{"data": {"id": "1", "geo": {"place_id": "01a9a39529b27f36"}}}`))
+	if _, _, ok := placeOnly.Data.Geo.LatLon(); ok {
+		t.Errorf("got coordinates for %+v, want none", placeOnly.Data.Geo)
+	}
+
+	absent, _ := Unmarshal([]byte(`{"data": {"id": "1"}}`))
+	if _, _, ok := absent.Data.Geo.LatLon(); absent.Data.Geo != nil || ok {
+		t.Errorf("got %+v, want no geo", absent.Data.Geo)
+	}
+}
+
+func TestUnmarshalDecodesEntities(t *testing.T) {
+	payload := `{
+		"data": {
+			"id": "1",
+			"text": "#golang news from @twitterdev https://t.co/abc",
+			"entities": {
+				"hashtags": [{"start": 0, "end": 7, "tag": "golang"}],
+				"mentions": [{"start": 18, "end": 29, "username": "twitterdev", "id": "2244994945"}],
+				"urls": [{"start": 30, "end": 53, "url": "https://t.co/abc", "expanded_url": "https://go.dev/blog", "display_url": "go.dev/blog"}],
+				"annotations": [{"start": 1, "end": 6, "probability": 0.6, "type": "Product", "normalized_text": "golang"}]
+			}
+		}
+	}`
+
+	result, err := Unmarshal([]byte(payload))
+	if err != nil {
+		t.Fatalf("got err %v", err)
+	}
+
+	entities := result.Data.Entities
+	if entities == nil {
+		t.Fatal("expected entities")
+	}
+
+	if len(entities.Hashtags) != 1 || entities.Hashtags[0].Tag != "golang" {
+		t.Errorf("got %+v, want golang hashtag", entities.Hashtags)
+	}
+
+	if len(entities.Mentions) != 1 || entities.Mentions[0].Username != "twitterdev" {
+		t.Errorf("got %+v, want twitterdev mention", entities.Mentions)
+	}
+
+	if len(entities.URLs) != 1 || entities.URLs[0].ExpandedURL != "https://go.dev/blog" || entities.URLs[0].DisplayURL != "go.dev/blog" {
+		t.Errorf("got %+v, want go.dev url", entities.URLs)
+	}
+
+	if len(entities.Annotations) != 1 || entities.Annotations[0].Type != "Product" {
+		t.Errorf("got %+v, want Product annotation", entities.Annotations)
+	}
+}
+
+func TestUnmarshalDecodesReplySettings(t *testing.T) {
+	result, err := Unmarshal([]byte(`{"data": {"id": "1", "reply_settings": "mentionedUsers"}}`))
+	if err != nil {
+		t.Fatalf("got err %v", err)
+	}
+
+	if result.Data.ReplySettings != ReplySettingsMentionedUsers {
+		t.Errorf("got %q, want %q", result.Data.ReplySettings, ReplySettingsMentionedUsers)
+	}
+}
+
+func TestUnmarshalDecodesLangAndSource(t *testing.T) {
+	result, err := Unmarshal([]byte(`{"data": {"id": "1", "lang": "en", "source": "Twitter Web App"}}`))
+	if err != nil {
+		t.Fatalf("got err %v", err)
+	}
+
+	if result.Data.Lang != "en" || result.Data.Source != "Twitter Web App" {
+		t.Errorf("got %+v, want lang and source", result.Data)
+	}
+
+	absent, _ := Unmarshal([]byte(`{"data": {"id": "1"}}`))
+	if absent.Data.Lang != "" || absent.Data.Source != "" {
+		t.Errorf("got %+v, want empty lang and source", absent.Data)
+	}
+}
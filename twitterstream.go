@@ -2,20 +2,75 @@
 package twitterstream
 
 import (
+	"net/http"
+	"net/url"
+
 	"dev.freespoke.com/twitter-stream/httpclient"
 	"dev.freespoke.com/twitter-stream/rules"
 	"dev.freespoke.com/twitter-stream/stream"
 	"dev.freespoke.com/twitter-stream/token_generator"
 )
 
-type TwitterApi struct {
-	Rules  rules.IRules
-	Stream stream.IStream
+type (
+	TwitterApi struct {
+		Rules  rules.IRules
+		Stream stream.IStream
+	}
+
+	// Option configures the clients created by NewTwitterStream and NewTokenGenerator.
+	Option func(*options)
+
+	options struct {
+		httpClient     []httpclient.Option
+		stream         []stream.Option
+		rules          []rules.Option
+		failoverTokens []string
+	}
+)
+
+// WithStreamOptions applies stream options, such as `stream.WithInitialConnectRetries`, to the stream.
+func WithStreamOptions(opts ...stream.Option) Option {
+	return func(o *options) {
+		o.stream = append(o.stream, opts...)
+	}
+}
+
+// WithRulesOptions applies rules options, such as `rules.WithChunkSize`, to TwitterApi.Rules.
+func WithRulesOptions(opts ...rules.Option) Option {
+	return func(o *options) {
+		o.rules = append(o.rules, opts...)
+	}
+}
+
+// WithHttpClientOptions applies httpclient options, such as `httpclient.WithBaseURL`, to every request made.
+func WithHttpClientOptions(opts ...httpclient.Option) Option {
+	return func(o *options) {
+		o.httpClient = append(o.httpClient, opts...)
+	}
+}
+
+// WithFailoverTokens adds bearer tokens of other apps the stream fails over to when the active token is rejected
+// or has reached its connection limit. See `stream.WithFailoverClients` for the rotation policy.
+// Rules are stored per app, so every app must have the same rules, e.g. with `stream.WithManagedRules`.
+// TwitterApi.Rules manages the rules of whichever app the stream is currently connected with.
+func WithFailoverTokens(tokens ...string) Option {
+	return func(o *options) {
+		o.failoverTokens = append(o.failoverTokens, tokens...)
+	}
+}
+
+func newOptions(opts []Option) *options {
+	o := new(options)
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
 }
 
 // NewTokenGenerator creates a TokenGenerator which can request a Bearer token using a twitter api key and secret.
-func NewTokenGenerator() token_generator.ITokenGenerator {
-	client := httpclient.NewHttpClient("")
+func NewTokenGenerator(opts ...Option) token_generator.ITokenGenerator {
+	o := newOptions(opts)
+	client := httpclient.NewHttpClient("", o.httpClient...)
 	tokenGenerator := token_generator.NewTokenGenerator(client)
 	return tokenGenerator
 }
@@ -40,9 +95,46 @@ func NewStreamQueryParamsBuilder() stream.IStreamQueryParamsBuilder {
 
 // NewTwitterStream consumes a twitter Bearer token.
 // It is used to interact with Twitter's v2 filtered streaming API
-func NewTwitterStream(token string) *TwitterApi {
-	client := httpclient.NewHttpClient(token)
-	rules := rules.NewRules(client)
-	stream := stream.NewStream(client, stream.NewStreamResponseBodyReader())
+func NewTwitterStream(token string, opts ...Option) *TwitterApi {
+	o := newOptions(opts)
+	client := httpclient.NewHttpClient(token, o.httpClient...)
+
+	streamOpts := o.stream
+	if len(o.failoverTokens) > 0 {
+		var clients []httpclient.IHttpClient
+		for _, failoverToken := range o.failoverTokens {
+			clients = append(clients, httpclient.NewHttpClient(failoverToken, o.httpClient...))
+		}
+		streamOpts = append([]stream.Option{stream.WithFailoverClients(clients...)}, streamOpts...)
+	}
+
+	stream := stream.NewStream(client, stream.NewStreamResponseBodyReader(), streamOpts...)
+	rules := rules.NewRules(activeClient{stream}, o.rules...)
 	return &TwitterApi{Rules: rules, Stream: stream}
 }
+
+// activeClient makes requests with the client the stream is currently connected with,
+// so rules follow the stream when it fails over to other credentials.
+type activeClient struct {
+	stream stream.IStream
+}
+
+func (c activeClient) NewHttpRequest(opts *httpclient.RequestOpts) (*http.Response, error) {
+	return c.stream.ActiveClient().NewHttpRequest(opts)
+}
+
+func (c activeClient) GetRules() (*http.Response, error) {
+	return c.stream.ActiveClient().GetRules()
+}
+
+func (c activeClient) GetSearchStream(queryParams *url.Values) (*http.Response, error) {
+	return c.stream.ActiveClient().GetSearchStream(queryParams)
+}
+
+func (c activeClient) AddRules(queryParams *url.Values, body string) (*http.Response, error) {
+	return c.stream.ActiveClient().AddRules(queryParams, body)
+}
+
+func (c activeClient) GenerateUrl(name string, queryParams *url.Values) (string, error) {
+	return c.stream.ActiveClient().GenerateUrl(name, queryParams)
+}